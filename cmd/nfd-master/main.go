@@ -18,12 +18,13 @@ package main
 
 import (
 	"fmt"
-	"log"
+	"os"
 	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/docopt/docopt-go"
+	"sigs.k8s.io/node-feature-discovery/pkg/logger"
 	master "sigs.k8s.io/node-feature-discovery/pkg/nfd-master"
 	"sigs.k8s.io/node-feature-discovery/pkg/version"
 )
@@ -36,23 +37,26 @@ const (
 func main() {
 	// Assert that the version is known
 	if version.Undefined() {
-		log.Print("WARNING: version not set! Set -ldflags \"-X sigs.k8s.io/node-feature-discovery/pkg/version.version=`git describe --tags --dirty --always`\" during build or run.")
+		logger.Warning("version not set! Set -ldflags \"-X sigs.k8s.io/node-feature-discovery/pkg/version.version=`git describe --tags --dirty --always`\" during build or run.")
 	}
 
 	// Parse command-line arguments.
 	args, err := argsParse(nil)
 	if err != nil {
-		log.Fatalf("failed to parse command line: %v", err)
+		logger.Error(err, "failed to parse command line")
+		os.Exit(1)
 	}
 
 	// Get new NfdMaster instance
 	instance, err := master.NewNfdMaster(args)
 	if err != nil {
-		log.Fatalf("Failed to initialize NfdMaster instance: %v", err)
+		logger.Error(err, "failed to initialize NfdMaster instance")
+		os.Exit(1)
 	}
 
 	if err = instance.Run(); err != nil {
-		log.Fatalf("ERROR: %v", err)
+		logger.Error(err, "nfd-master failed")
+		os.Exit(1)
 	}
 }
 
@@ -66,7 +70,7 @@ func argsParse(argv []string) (master.Args, error) {
   %s [--prune] [--no-publish] [--label-whitelist=<pattern>] [--port=<port>]
      [--ca-file=<path>] [--cert-file=<path>] [--key-file=<path>]
      [--verify-node-name] [--extra-label-ns=<list>] [--resource-labels=<list>]
-     [--kubeconfig=<path>]
+     [--kubeconfig=<path>] [--verbosity=<level>] [--log-format=<format>]
   %s -h | --help
   %s --version
 
@@ -97,7 +101,11 @@ func argsParse(argv []string) (master.Args, error) {
   --extra-label-ns=<list>         Comma separated list of allowed extra label namespaces
                                   [Default: ]
   --resource-labels=<list>        Comma separated list of labels to be exposed as extended resources.
-                                  [Default: ]`,
+                                  [Default: ]
+  --verbosity=<level>             Verbosity level of info log messages.
+                                  [Default: 0]
+  --log-format=<format>           Format of log messages (text or json).
+                                  [Default: text]`,
 		ProgramName,
 		ProgramName,
 		ProgramName,
@@ -126,6 +134,11 @@ func argsParse(argv []string) (master.Args, error) {
 	args.ResourceLabels = strings.Split(arguments["--resource-labels"].(string), ",")
 	args.Prune = arguments["--prune"].(bool)
 	args.Kubeconfig = arguments["--kubeconfig"].(string)
+	args.LogFormat = arguments["--log-format"].(string)
+	args.Verbosity, err = strconv.Atoi(arguments["--verbosity"].(string))
+	if err != nil {
+		return args, fmt.Errorf("invalid --verbosity defined: %s", err)
+	}
 
 	return args, nil
 }
@@ -29,6 +29,8 @@ func TestArgsParse(t *testing.T) {
 			Convey("noPublish is set and args.sources is set to the default value", func() {
 				So(args.NoPublish, ShouldBeTrue)
 				So(len(args.LabelWhiteList.String()), ShouldEqual, 0)
+				So(args.Verbosity, ShouldEqual, 0)
+				So(args.LogFormat, ShouldEqual, "text")
 				So(err, ShouldBeNil)
 			})
 		})
@@ -45,6 +47,12 @@ func TestArgsParse(t *testing.T) {
 				So(err, ShouldBeNil)
 			})
 		})
+		Convey("When invalid --verbosity is defined", func() {
+			_, err := argsParse([]string{"--verbosity=high"})
+			Convey("argsParse should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
 		Convey("When invalid --port is defined", func() {
 			_, err := argsParse([]string{"--port=123a"})
 			Convey("argsParse should fail", func() {
@@ -18,11 +18,15 @@ package main
 
 import (
 	"fmt"
-	"log"
+	"os"
+	"os/signal"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/docopt/docopt-go"
+	"sigs.k8s.io/node-feature-discovery/pkg/logger"
 	worker "sigs.k8s.io/node-feature-discovery/pkg/nfd-worker"
 	"sigs.k8s.io/node-feature-discovery/pkg/version"
 )
@@ -35,23 +39,37 @@ const (
 func main() {
 	// Assert that the version is known
 	if version.Undefined() {
-		log.Printf("WARNING: version not set! Set -ldflags \"-X sigs.k8s.io/node-feature-discovery/pkg/version.version=`git describe --tags --dirty --always`\" during build or run.")
+		logger.Warning("version not set! Set -ldflags \"-X sigs.k8s.io/node-feature-discovery/pkg/version.version=`git describe --tags --dirty --always`\" during build or run.")
 	}
 
 	// Parse command-line arguments.
 	args, err := argsParse(nil)
 	if err != nil {
-		log.Fatalf("failed to parse command line: %v", err)
+		logger.Error(err, "failed to parse command line")
+		os.Exit(1)
 	}
 
 	// Get new NfdWorker instance
 	instance, err := worker.NewNfdWorker(args)
 	if err != nil {
-		log.Fatalf("Failed to initialize NfdWorker instance: %v", err)
+		logger.Error(err, "failed to initialize NfdWorker instance")
+		os.Exit(1)
 	}
 
+	// Stop gracefully on SIGTERM and SIGINT
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		sig := <-sigs
+		// Let a second signal terminate the process immediately
+		signal.Stop(sigs)
+		logger.Info("signal received, stopping", "signal", sig.String())
+		instance.Stop()
+	}()
+
 	if err = instance.Run(); err != nil {
-		log.Fatalf("ERROR: %v", err)
+		logger.Error(err, "nfd-worker failed")
+		os.Exit(1)
 	}
 }
 
@@ -66,6 +84,19 @@ func argsParse(argv []string) (worker.Args, error) {
      [--oneshot | --sleep-interval=<seconds>] [--config=<path>]
      [--options=<config>] [--server=<server>] [--server-name-override=<name>]
      [--ca-file=<path>] [--cert-file=<path>] [--key-file=<path>]
+     [--export-format=<format>] [--export-file=<path>]
+     [--export-group-by-source] [--introspection-addr=<addr>]
+     [--plugin-socket=<path>] [--source-timeout=<duration>]
+     [--host-prefix=<prefix>] [--replay=<path>] [--withdraw-labels]
+     [--verbosity=<level>] [--log-format=<format>]
+  %s explain [--feature=<name>] [--sources=<sources>]
+     [--label-whitelist=<pattern>] [--config=<path>] [--options=<config>]
+     [--master-extra-label-ns=<list>] [--master-label-whitelist=<pattern>]
+     [--replay=<path>] [--verbosity=<level>] [--log-format=<format>]
+  %s validate-config [--config=<path>] [--options=<config>]
+  %s snapshot [--snapshot-file=<path>] [--sources=<sources>] [--config=<path>]
+     [--options=<config>] [--host-prefix=<prefix>]
+     [--verbosity=<level>] [--log-format=<format>]
   %s -h | --help
   %s --version
 
@@ -91,7 +122,8 @@ func argsParse(argv []string) (worker.Args, error) {
                               in testing
                               [Default: ]
   --sources=<sources>         Comma separated list of feature sources.
-                              [Default: cpu,custom,iommu,kernel,local,memory,network,pci,storage,system,usb]
+                              Overridden by core.sources of the config file.
+                              [Default: cpu,custom,iommu,kernel,local,memory,network,pci,storage,system,usb,virt]
   --no-publish                Do not publish discovered features to the
                               cluster-local Kubernetes API server.
   --label-whitelist=<pattern> Regular expression to filter label names to
@@ -99,6 +131,44 @@ func argsParse(argv []string) (worker.Args, error) {
                               NB: the label namespace is omitted i.e. the filter
                               is only applied to the name part after '/'.
                               [Default: ]
+  --export-format=<format>    Export discovered features in the given format
+                              (json, yaml or env) on every discovery cycle.
+                              Empty value disables exporting. [Default: ]
+  --export-file=<path>        File to export discovered features to. '-'
+                              means stdout. [Default: -]
+  --export-group-by-source    Group exported features by feature source.
+  --introspection-addr=<addr> Address where to serve the local introspection
+                              API. Empty value disables the API. [Default: ]
+  --plugin-socket=<path>      Unix socket where to serve the registration
+                              service for feature source plugins. Empty value
+                              disables plugins. [Default: ]
+  --source-timeout=<duration> Time to wait for one feature source to complete
+                              discovery. Non-positive value disables the
+                              timeout. [Default: 0s]
+  --verbosity=<level>         Verbosity level of info log messages.
+                              [Default: 0]
+  --log-format=<format>       Format of log messages (text or json).
+                              [Default: text]
+  --feature=<name>            Only explain the feature (or label) with the
+                              given name. [Default: ]
+  --master-extra-label-ns=<list> Comma separated list of extra label namespaces
+                              allowed by nfd-master, used in explain mode.
+                              [Default: ]
+  --master-label-whitelist=<pattern> Label whitelist of nfd-master, used in
+                              explain mode. [Default: ]
+  --host-prefix=<prefix>      Prefix of the host system directories, e.g.
+                              '/host-' means that /sys of the host is found
+                              at /host-sys. Empty value means the built-in
+                              default. [Default: ]
+  --replay=<path>             Inspect a snapshot archive captured with the
+                              snapshot command instead of the host. Labels
+                              are never published when replaying.
+                              [Default: ]
+  --snapshot-file=<path>      File to write the snapshot archive to. '-'
+                              means stdout. [Default: nfd-snapshot.tar.gz]
+  --withdraw-labels           Remove the labels, extended resources and
+                              annotations of the node via nfd-master when
+                              stopped by SIGTERM or SIGINT.
   --oneshot                   Label once and exit.
   --sleep-interval=<seconds>  Time to sleep between re-labeling. Non-positive
                               value implies no re-labeling (i.e. infinite
@@ -107,6 +177,9 @@ func argsParse(argv []string) (worker.Args, error) {
 		ProgramName,
 		ProgramName,
 		ProgramName,
+		ProgramName,
+		ProgramName,
+		ProgramName,
 	)
 
 	arguments, _ := docopt.ParseArgs(usage, argv,
@@ -117,9 +190,21 @@ func argsParse(argv []string) (worker.Args, error) {
 	args.CaFile = arguments["--ca-file"].(string)
 	args.CertFile = arguments["--cert-file"].(string)
 	args.ConfigFile = arguments["--config"].(string)
+	args.ExportFile = arguments["--export-file"].(string)
+	args.ExportFormat = arguments["--export-format"].(string)
+	args.ExportGroupBySource = arguments["--export-group-by-source"].(bool)
+	args.Explain = arguments["explain"].(bool)
+	args.ExplainFeature = arguments["--feature"].(string)
+	args.HostPrefix = arguments["--host-prefix"].(string)
+	args.IntrospectionAddr = arguments["--introspection-addr"].(string)
 	args.KeyFile = arguments["--key-file"].(string)
+	args.LogFormat = arguments["--log-format"].(string)
+	args.MasterExtraLabelNs = strings.Split(arguments["--master-extra-label-ns"].(string), ",")
+	args.MasterLabelWhiteList = arguments["--master-label-whitelist"].(string)
 	args.NoPublish = arguments["--no-publish"].(bool)
 	args.Options = arguments["--options"].(string)
+	args.PluginSocket = arguments["--plugin-socket"].(string)
+	args.Replay = arguments["--replay"].(string)
 	args.Server = arguments["--server"].(string)
 	args.ServerNameOverride = arguments["--server-name-override"].(string)
 	args.Sources = strings.Split(arguments["--sources"].(string), ",")
@@ -129,5 +214,17 @@ func argsParse(argv []string) (worker.Args, error) {
 	if err != nil {
 		return args, fmt.Errorf("invalid --sleep-interval specified: %s", err.Error())
 	}
+	args.Snapshot = arguments["snapshot"].(bool)
+	args.SnapshotFile = arguments["--snapshot-file"].(string)
+	args.ValidateConfig = arguments["validate-config"].(bool)
+	args.SourceTimeout, err = time.ParseDuration(arguments["--source-timeout"].(string))
+	if err != nil {
+		return args, fmt.Errorf("invalid --source-timeout specified: %s", err.Error())
+	}
+	args.WithdrawLabels = arguments["--withdraw-labels"].(bool)
+	args.Verbosity, err = strconv.Atoi(arguments["--verbosity"].(string))
+	if err != nil {
+		return args, fmt.Errorf("invalid --verbosity specified: %s", err.Error())
+	}
 	return args, nil
 }
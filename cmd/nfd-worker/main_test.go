@@ -23,7 +23,7 @@ import (
 	. "github.com/smartystreets/goconvey/convey"
 )
 
-var allSources = []string{"cpu", "custom", "iommu", "kernel", "local", "memory", "network", "pci", "storage", "system", "usb"}
+var allSources = []string{"cpu", "custom", "iommu", "kernel", "local", "memory", "network", "pci", "storage", "system", "usb", "virt"}
 
 func TestArgsParse(t *testing.T) {
 	Convey("When parsing command line arguments", t, func() {
@@ -36,15 +36,107 @@ func TestArgsParse(t *testing.T) {
 				So(args.Oneshot, ShouldBeTrue)
 				So(args.Sources, ShouldResemble, allSources)
 				So(len(args.LabelWhiteList), ShouldEqual, 0)
+				So(args.ExportFormat, ShouldEqual, "")
+				So(args.ExportFile, ShouldEqual, "-")
+				So(args.ExportGroupBySource, ShouldBeFalse)
+				So(args.IntrospectionAddr, ShouldEqual, "")
+				So(args.PluginSocket, ShouldEqual, "")
+				So(args.Verbosity, ShouldEqual, 0)
+				So(args.LogFormat, ShouldEqual, "text")
+				So(args.Explain, ShouldBeFalse)
+				So(args.SourceTimeout, ShouldEqual, 0)
+				So(args.HostPrefix, ShouldEqual, "")
 				So(err, ShouldBeNil)
 			})
 		})
 
+		Convey("When export flags are passed", func() {
+			args, err := argsParse([]string{"--export-format=yaml", "--export-file=/tmp/features.yaml", "--export-group-by-source"})
+
+			Convey("export args are set to appropriate values", func() {
+				So(args.ExportFormat, ShouldEqual, "yaml")
+				So(args.ExportFile, ShouldEqual, "/tmp/features.yaml")
+				So(args.ExportGroupBySource, ShouldBeTrue)
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When logging flags are passed", func() {
+			args, err := argsParse([]string{"--verbosity=2", "--log-format=json"})
+
+			Convey("logging args are set to appropriate values", func() {
+				So(args.Verbosity, ShouldEqual, 2)
+				So(args.LogFormat, ShouldEqual, "json")
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When explain command is used", func() {
+			args, err := argsParse([]string{"explain", "--feature=cpuid.AVX", "--master-extra-label-ns=vendor.io,example.com", "--master-label-whitelist=cpuid"})
+
+			Convey("explain args are set to appropriate values", func() {
+				So(args.Explain, ShouldBeTrue)
+				So(args.ExplainFeature, ShouldEqual, "cpuid.AVX")
+				So(args.MasterExtraLabelNs, ShouldResemble, []string{"vendor.io", "example.com"})
+				So(args.MasterLabelWhiteList, ShouldEqual, "cpuid")
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When validate-config command is used", func() {
+			args, err := argsParse([]string{"validate-config", "--config=/tmp/nfd-worker.conf"})
+
+			Convey("validate-config args are set to appropriate values", func() {
+				So(args.ValidateConfig, ShouldBeTrue)
+				So(args.ConfigFile, ShouldEqual, "/tmp/nfd-worker.conf")
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When snapshot command is used", func() {
+			args, err := argsParse([]string{"snapshot", "--snapshot-file=/tmp/snapshot.tar.gz", "--host-prefix=/host-"})
+
+			Convey("snapshot args are set to appropriate values", func() {
+				So(args.Snapshot, ShouldBeTrue)
+				So(args.SnapshotFile, ShouldEqual, "/tmp/snapshot.tar.gz")
+				So(args.HostPrefix, ShouldEqual, "/host-")
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When --replay is specified", func() {
+			args, err := argsParse([]string{"--replay=/tmp/snapshot.tar.gz", "--oneshot"})
+
+			Convey("args.Replay is set to appropriate value", func() {
+				So(args.Snapshot, ShouldBeFalse)
+				So(args.Replay, ShouldEqual, "/tmp/snapshot.tar.gz")
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When --withdraw-labels is specified", func() {
+			args, err := argsParse([]string{"--withdraw-labels"})
+
+			Convey("args.WithdrawLabels is set to appropriate value", func() {
+				So(args.WithdrawLabels, ShouldBeTrue)
+				So(err, ShouldBeNil)
+			})
+		})
+
+		Convey("When invalid --verbosity is specified", func() {
+			_, err := argsParse([]string{"--verbosity=high"})
+
+			Convey("argsParse should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
 		Convey("When --sources flag is passed and set to some values, --sleep-inteval is specified", func() {
-			args, err := argsParse([]string{"--sources=fake1,fake2,fake3", "--sleep-interval=30s"})
+			args, err := argsParse([]string{"--sources=fake1,fake2,fake3", "--sleep-interval=30s", "--source-timeout=10s"})
 
 			Convey("args.sources is set to appropriate values", func() {
 				So(args.SleepInterval, ShouldEqual, 30*time.Second)
+				So(args.SourceTimeout, ShouldEqual, 10*time.Second)
 				So(args.NoPublish, ShouldBeFalse)
 				So(args.Oneshot, ShouldBeFalse)
 				So(args.Sources, ShouldResemble, []string{"fake1", "fake2", "fake3"})
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuid
+
+import (
+	"sort"
+	"sync"
+)
+
+type ReturnValue struct {
+	EAX, EBX, ECX, EDX uint32
+}
+
+// Record is the result of one CPUID query
+type Record struct {
+	Leaf    uint32      `json:"leaf"`
+	Subleaf uint32      `json:"subleaf"`
+	Value   ReturnValue `json:"value"`
+}
+
+type leaf struct {
+	eax, ecx uint32
+}
+
+var (
+	mutex sync.Mutex
+	// records are the results of all queries run on the CPU
+	records = map[leaf]ReturnValue{}
+	// replay, if non-nil, holds the results returned instead of querying
+	// the CPU
+	replay map[leaf]ReturnValue
+)
+
+// lookup returns the result of a query, either from the replayed records or
+// by running the query with the given function
+func lookup(eax, ecx uint32, query func() ReturnValue) *ReturnValue {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	l := leaf{eax, ecx}
+	if replay != nil {
+		// Leaves missing from the replayed records are unsupported
+		r := replay[l]
+		return &r
+	}
+	r := query()
+	records[l] = r
+	return &r
+}
+
+// Records returns the results of all the queries that have been run on the
+// CPU, sorted by leaf and subleaf
+func Records() []Record {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	ret := make([]Record, 0, len(records))
+	for l, r := range records {
+		ret = append(ret, Record{Leaf: l.eax, Subleaf: l.ecx, Value: r})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Leaf != ret[j].Leaf {
+			return ret[i].Leaf < ret[j].Leaf
+		}
+		return ret[i].Subleaf < ret[j].Subleaf
+	})
+	return ret
+}
+
+// SetReplay makes Cpuid return results from the given records, e.g. ones
+// captured on another node, instead of querying the CPU. Nil restores the
+// default behavior.
+func SetReplay(rs []Record) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if rs == nil {
+		replay = nil
+		return
+	}
+	replay = make(map[leaf]ReturnValue, len(rs))
+	for _, r := range rs {
+		replay[leaf{r.Leaf, r.Subleaf}] = r.Value
+	}
+}
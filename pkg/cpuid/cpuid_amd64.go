@@ -16,14 +16,12 @@ limitations under the License.
 
 package cpuid
 
-type ReturnValue struct {
-	EAX, EBX, ECX, EDX uint32
-}
-
 func Cpuid(eax, ecx uint32) *ReturnValue {
-	r := &ReturnValue{}
-	r.EAX, r.EBX, r.ECX, r.EDX = cpuidAsm(eax, ecx)
-	return r
+	return lookup(eax, ecx, func() ReturnValue {
+		r := ReturnValue{}
+		r.EAX, r.EBX, r.ECX, r.EDX = cpuidAsm(eax, ecx)
+		return r
+	})
 }
 
 func cpuidAsm(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
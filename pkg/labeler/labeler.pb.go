@@ -3,13 +3,14 @@
 
 package labeler
 
-import proto "github.com/golang/protobuf/proto"
-import fmt "fmt"
-import math "math"
-
 import (
-	context "golang.org/x/net/context"
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
 	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -21,31 +22,33 @@ var _ = math.Inf
 // is compatible with the proto package it is being compiled against.
 // A compilation error at this line likely means your copy of the
 // proto package needs to be updated.
-const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type SetLabelsRequest struct {
-	NfdVersion           string            `protobuf:"bytes,1,opt,name=nfd_version,json=nfdVersion" json:"nfd_version,omitempty"`
-	NodeName             string            `protobuf:"bytes,2,opt,name=node_name,json=nodeName" json:"node_name,omitempty"`
-	Labels               map[string]string `protobuf:"bytes,3,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	NfdVersion           string                   `protobuf:"bytes,1,opt,name=nfd_version,json=nfdVersion,proto3" json:"nfd_version,omitempty"`
+	NodeName             string                   `protobuf:"bytes,2,opt,name=node_name,json=nodeName,proto3" json:"node_name,omitempty"`
+	Labels               map[string]string        `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	SourceStatus         map[string]*SourceStatus `protobuf:"bytes,4,rep,name=source_status,json=sourceStatus,proto3" json:"source_status,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
 }
 
 func (m *SetLabelsRequest) Reset()         { *m = SetLabelsRequest{} }
 func (m *SetLabelsRequest) String() string { return proto.CompactTextString(m) }
 func (*SetLabelsRequest) ProtoMessage()    {}
 func (*SetLabelsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_labeler_06d914ce56652184, []int{0}
+	return fileDescriptor_5f7992ba896eeca0, []int{0}
 }
+
 func (m *SetLabelsRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SetLabelsRequest.Unmarshal(m, b)
 }
 func (m *SetLabelsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_SetLabelsRequest.Marshal(b, m, deterministic)
 }
-func (dst *SetLabelsRequest) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SetLabelsRequest.Merge(dst, src)
+func (m *SetLabelsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetLabelsRequest.Merge(m, src)
 }
 func (m *SetLabelsRequest) XXX_Size() int {
 	return xxx_messageInfo_SetLabelsRequest.Size(m)
@@ -77,6 +80,60 @@ func (m *SetLabelsRequest) GetLabels() map[string]string {
 	return nil
 }
 
+func (m *SetLabelsRequest) GetSourceStatus() map[string]*SourceStatus {
+	if m != nil {
+		return m.SourceStatus
+	}
+	return nil
+}
+
+type SourceStatus struct {
+	Status               string   `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Message              string   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SourceStatus) Reset()         { *m = SourceStatus{} }
+func (m *SourceStatus) String() string { return proto.CompactTextString(m) }
+func (*SourceStatus) ProtoMessage()    {}
+func (*SourceStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5f7992ba896eeca0, []int{1}
+}
+
+func (m *SourceStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SourceStatus.Unmarshal(m, b)
+}
+func (m *SourceStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SourceStatus.Marshal(b, m, deterministic)
+}
+func (m *SourceStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SourceStatus.Merge(m, src)
+}
+func (m *SourceStatus) XXX_Size() int {
+	return xxx_messageInfo_SourceStatus.Size(m)
+}
+func (m *SourceStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_SourceStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SourceStatus proto.InternalMessageInfo
+
+func (m *SourceStatus) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *SourceStatus) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
 type SetLabelsReply struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -87,16 +144,17 @@ func (m *SetLabelsReply) Reset()         { *m = SetLabelsReply{} }
 func (m *SetLabelsReply) String() string { return proto.CompactTextString(m) }
 func (*SetLabelsReply) ProtoMessage()    {}
 func (*SetLabelsReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_labeler_06d914ce56652184, []int{1}
+	return fileDescriptor_5f7992ba896eeca0, []int{2}
 }
+
 func (m *SetLabelsReply) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SetLabelsReply.Unmarshal(m, b)
 }
 func (m *SetLabelsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	return xxx_messageInfo_SetLabelsReply.Marshal(b, m, deterministic)
 }
-func (dst *SetLabelsReply) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SetLabelsReply.Merge(dst, src)
+func (m *SetLabelsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetLabelsReply.Merge(m, src)
 }
 func (m *SetLabelsReply) XXX_Size() int {
 	return xxx_messageInfo_SetLabelsReply.Size(m)
@@ -107,10 +165,120 @@ func (m *SetLabelsReply) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_SetLabelsReply proto.InternalMessageInfo
 
+type RemoveLabelsRequest struct {
+	NfdVersion           string   `protobuf:"bytes,1,opt,name=nfd_version,json=nfdVersion,proto3" json:"nfd_version,omitempty"`
+	NodeName             string   `protobuf:"bytes,2,opt,name=node_name,json=nodeName,proto3" json:"node_name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveLabelsRequest) Reset()         { *m = RemoveLabelsRequest{} }
+func (m *RemoveLabelsRequest) String() string { return proto.CompactTextString(m) }
+func (*RemoveLabelsRequest) ProtoMessage()    {}
+func (*RemoveLabelsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5f7992ba896eeca0, []int{3}
+}
+
+func (m *RemoveLabelsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveLabelsRequest.Unmarshal(m, b)
+}
+func (m *RemoveLabelsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveLabelsRequest.Marshal(b, m, deterministic)
+}
+func (m *RemoveLabelsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveLabelsRequest.Merge(m, src)
+}
+func (m *RemoveLabelsRequest) XXX_Size() int {
+	return xxx_messageInfo_RemoveLabelsRequest.Size(m)
+}
+func (m *RemoveLabelsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveLabelsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveLabelsRequest proto.InternalMessageInfo
+
+func (m *RemoveLabelsRequest) GetNfdVersion() string {
+	if m != nil {
+		return m.NfdVersion
+	}
+	return ""
+}
+
+func (m *RemoveLabelsRequest) GetNodeName() string {
+	if m != nil {
+		return m.NodeName
+	}
+	return ""
+}
+
+type RemoveLabelsReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveLabelsReply) Reset()         { *m = RemoveLabelsReply{} }
+func (m *RemoveLabelsReply) String() string { return proto.CompactTextString(m) }
+func (*RemoveLabelsReply) ProtoMessage()    {}
+func (*RemoveLabelsReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5f7992ba896eeca0, []int{4}
+}
+
+func (m *RemoveLabelsReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveLabelsReply.Unmarshal(m, b)
+}
+func (m *RemoveLabelsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveLabelsReply.Marshal(b, m, deterministic)
+}
+func (m *RemoveLabelsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveLabelsReply.Merge(m, src)
+}
+func (m *RemoveLabelsReply) XXX_Size() int {
+	return xxx_messageInfo_RemoveLabelsReply.Size(m)
+}
+func (m *RemoveLabelsReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveLabelsReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveLabelsReply proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterType((*SetLabelsRequest)(nil), "labeler.SetLabelsRequest")
 	proto.RegisterMapType((map[string]string)(nil), "labeler.SetLabelsRequest.LabelsEntry")
+	proto.RegisterMapType((map[string]*SourceStatus)(nil), "labeler.SetLabelsRequest.SourceStatusEntry")
+	proto.RegisterType((*SourceStatus)(nil), "labeler.SourceStatus")
 	proto.RegisterType((*SetLabelsReply)(nil), "labeler.SetLabelsReply")
+	proto.RegisterType((*RemoveLabelsRequest)(nil), "labeler.RemoveLabelsRequest")
+	proto.RegisterType((*RemoveLabelsReply)(nil), "labeler.RemoveLabelsReply")
+}
+
+func init() { proto.RegisterFile("labeler.proto", fileDescriptor_5f7992ba896eeca0) }
+
+var fileDescriptor_5f7992ba896eeca0 = []byte{
+	// 338 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x92, 0xcf, 0x4a, 0xc3, 0x40,
+	0x10, 0xc6, 0x4d, 0xab, 0xad, 0x9d, 0xb4, 0xd2, 0x4e, 0xfd, 0x13, 0xa3, 0x60, 0x09, 0x08, 0x85,
+	0x42, 0x0f, 0xf5, 0xa2, 0x82, 0xa0, 0x07, 0x2f, 0x52, 0x44, 0x12, 0xe8, 0xb5, 0xa4, 0x76, 0x2a,
+	0x62, 0x92, 0xad, 0xd9, 0x4d, 0x21, 0x6f, 0xe2, 0x83, 0xfa, 0x00, 0x92, 0xdd, 0xa4, 0xae, 0xda,
+	0xdc, 0xbc, 0xe5, 0x9b, 0x99, 0xfd, 0xcd, 0xf7, 0x65, 0x17, 0x5a, 0x81, 0x3f, 0xa3, 0x80, 0xe2,
+	0xe1, 0x32, 0x66, 0x82, 0x61, 0x3d, 0x97, 0xce, 0x67, 0x05, 0xda, 0x1e, 0x89, 0x71, 0x26, 0xb9,
+	0x4b, 0xef, 0x09, 0x71, 0x81, 0x67, 0x60, 0x46, 0x8b, 0xf9, 0x74, 0x45, 0x31, 0x7f, 0x65, 0x91,
+	0x65, 0xf4, 0x8c, 0x7e, 0xc3, 0x85, 0x68, 0x31, 0x9f, 0xa8, 0x0a, 0x9e, 0x40, 0x23, 0x62, 0x73,
+	0x9a, 0x46, 0x7e, 0x48, 0x56, 0x45, 0xb6, 0x77, 0xb3, 0xc2, 0xa3, 0x1f, 0x12, 0xde, 0x40, 0x4d,
+	0xd2, 0xb9, 0x55, 0xed, 0x55, 0xfb, 0xe6, 0xe8, 0x7c, 0x58, 0xec, 0xfe, 0xbd, 0x68, 0xa8, 0xd4,
+	0x7d, 0x24, 0xe2, 0xd4, 0xcd, 0x0f, 0xe1, 0x13, 0xb4, 0x38, 0x4b, 0xe2, 0x67, 0x9a, 0x72, 0xe1,
+	0x8b, 0x84, 0x5b, 0xdb, 0x92, 0x32, 0x28, 0xa7, 0x78, 0x72, 0xdc, 0x93, 0xd3, 0x8a, 0xd5, 0xe4,
+	0x5a, 0xc9, 0xbe, 0x02, 0x53, 0x5b, 0x84, 0x6d, 0xa8, 0xbe, 0x51, 0x9a, 0xa7, 0xca, 0x3e, 0x71,
+	0x1f, 0x76, 0x56, 0x7e, 0x90, 0x14, 0x51, 0x94, 0xb8, 0xae, 0x5c, 0x1a, 0xf6, 0x04, 0x3a, 0x7f,
+	0xe8, 0x1b, 0x00, 0x03, 0x1d, 0x60, 0x8e, 0x0e, 0xbe, 0xbd, 0x6a, 0x87, 0x35, 0xae, 0x73, 0x0b,
+	0x4d, 0xbd, 0x85, 0x87, 0x50, 0xcb, 0xd3, 0x2a, 0x6a, 0xae, 0xd0, 0x82, 0x7a, 0x48, 0x9c, 0xfb,
+	0x2f, 0x85, 0xb7, 0x42, 0x3a, 0x6d, 0xd8, 0xd3, 0x7e, 0xc4, 0x32, 0x48, 0x1d, 0x0f, 0xba, 0x2e,
+	0x85, 0x6c, 0x45, 0xff, 0x78, 0x99, 0x4e, 0x17, 0x3a, 0x3f, 0xa1, 0xcb, 0x20, 0x1d, 0x7d, 0x18,
+	0x50, 0x1f, 0xab, 0x84, 0x78, 0x07, 0x8d, 0xb5, 0x0f, 0x3c, 0x2e, 0xbd, 0x24, 0xfb, 0x68, 0x53,
+	0x2b, 0xb3, 0xbd, 0x85, 0x0f, 0xd0, 0xd4, 0x77, 0xe0, 0xe9, 0x7a, 0x74, 0x43, 0x1e, 0xdb, 0x2e,
+	0xe9, 0x4a, 0xd6, 0xac, 0x26, 0xdf, 0xf7, 0xc5, 0xd7, 0x00, 0x21, 0xa7, 0xf6, 0xb9, 0xf0, 0x02,
+	0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -121,10 +289,12 @@ var _ grpc.ClientConn
 // is compatible with the grpc package it is being compiled against.
 const _ = grpc.SupportPackageIsVersion4
 
-// Client API for Labeler service
-
+// LabelerClient is the client API for Labeler service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type LabelerClient interface {
 	SetLabels(ctx context.Context, in *SetLabelsRequest, opts ...grpc.CallOption) (*SetLabelsReply, error)
+	RemoveLabels(ctx context.Context, in *RemoveLabelsRequest, opts ...grpc.CallOption) (*RemoveLabelsReply, error)
 }
 
 type labelerClient struct {
@@ -137,17 +307,37 @@ func NewLabelerClient(cc *grpc.ClientConn) LabelerClient {
 
 func (c *labelerClient) SetLabels(ctx context.Context, in *SetLabelsRequest, opts ...grpc.CallOption) (*SetLabelsReply, error) {
 	out := new(SetLabelsReply)
-	err := grpc.Invoke(ctx, "/labeler.Labeler/SetLabels", in, out, c.cc, opts...)
+	err := c.cc.Invoke(ctx, "/labeler.Labeler/SetLabels", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return out, nil
 }
 
-// Server API for Labeler service
+func (c *labelerClient) RemoveLabels(ctx context.Context, in *RemoveLabelsRequest, opts ...grpc.CallOption) (*RemoveLabelsReply, error) {
+	out := new(RemoveLabelsReply)
+	err := c.cc.Invoke(ctx, "/labeler.Labeler/RemoveLabels", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
 
+// LabelerServer is the server API for Labeler service.
 type LabelerServer interface {
 	SetLabels(context.Context, *SetLabelsRequest) (*SetLabelsReply, error)
+	RemoveLabels(context.Context, *RemoveLabelsRequest) (*RemoveLabelsReply, error)
+}
+
+// UnimplementedLabelerServer can be embedded to have forward compatible implementations.
+type UnimplementedLabelerServer struct {
+}
+
+func (*UnimplementedLabelerServer) SetLabels(ctx context.Context, req *SetLabelsRequest) (*SetLabelsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLabels not implemented")
+}
+func (*UnimplementedLabelerServer) RemoveLabels(ctx context.Context, req *RemoveLabelsRequest) (*RemoveLabelsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveLabels not implemented")
 }
 
 func RegisterLabelerServer(s *grpc.Server, srv LabelerServer) {
@@ -172,6 +362,24 @@ func _Labeler_SetLabels_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Labeler_RemoveLabels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveLabelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LabelerServer).RemoveLabels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/labeler.Labeler/RemoveLabels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LabelerServer).RemoveLabels(ctx, req.(*RemoveLabelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Labeler_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "labeler.Labeler",
 	HandlerType: (*LabelerServer)(nil),
@@ -180,27 +388,11 @@ var _Labeler_serviceDesc = grpc.ServiceDesc{
 			MethodName: "SetLabels",
 			Handler:    _Labeler_SetLabels_Handler,
 		},
+		{
+			MethodName: "RemoveLabels",
+			Handler:    _Labeler_RemoveLabels_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "labeler.proto",
 }
-
-func init() { proto.RegisterFile("labeler.proto", fileDescriptor_labeler_06d914ce56652184) }
-
-var fileDescriptor_labeler_06d914ce56652184 = []byte{
-	// 220 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0xcd, 0x49, 0x4c, 0x4a,
-	0xcd, 0x49, 0x2d, 0xd2, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x87, 0x72, 0x95, 0x4e, 0x31,
-	0x72, 0x09, 0x04, 0xa7, 0x96, 0xf8, 0x80, 0xb8, 0xc5, 0x41, 0xa9, 0x85, 0xa5, 0xa9, 0xc5, 0x25,
-	0x42, 0xf2, 0x5c, 0xdc, 0x79, 0x69, 0x29, 0xf1, 0x65, 0xa9, 0x45, 0xc5, 0x99, 0xf9, 0x79, 0x12,
-	0x8c, 0x0a, 0x8c, 0x1a, 0x9c, 0x41, 0x5c, 0x79, 0x69, 0x29, 0x61, 0x10, 0x11, 0x21, 0x69, 0x2e,
-	0xce, 0xbc, 0xfc, 0x94, 0xd4, 0xf8, 0xbc, 0xc4, 0xdc, 0x54, 0x09, 0x26, 0xb0, 0x34, 0x07, 0x48,
-	0xc0, 0x2f, 0x31, 0x37, 0x55, 0xc8, 0x96, 0x8b, 0x0d, 0x6c, 0x7a, 0xb1, 0x04, 0xb3, 0x02, 0xb3,
-	0x06, 0xb7, 0x91, 0xaa, 0x1e, 0xcc, 0x6e, 0x74, 0x8b, 0xf4, 0x20, 0x3c, 0xd7, 0xbc, 0x92, 0xa2,
-	0xca, 0x20, 0xa8, 0x26, 0x29, 0x4b, 0x2e, 0x6e, 0x24, 0x61, 0x21, 0x01, 0x2e, 0xe6, 0xec, 0xd4,
-	0x4a, 0xa8, 0x1b, 0x40, 0x4c, 0x21, 0x11, 0x2e, 0xd6, 0xb2, 0xc4, 0x9c, 0x52, 0x98, 0xc5, 0x10,
-	0x8e, 0x15, 0x93, 0x05, 0xa3, 0x92, 0x00, 0x17, 0x1f, 0x92, 0x15, 0x05, 0x39, 0x95, 0x46, 0x3e,
-	0x5c, 0xec, 0x3e, 0x10, 0xcb, 0x85, 0x1c, 0xb9, 0x38, 0xe1, 0x92, 0x42, 0x92, 0x38, 0xdd, 0x24,
-	0x25, 0x8e, 0x4d, 0xaa, 0x20, 0xa7, 0x52, 0x89, 0x21, 0x89, 0x0d, 0x1c, 0x78, 0xc6, 0x80, 0x00,
-	0x00, 0x00, 0xff, 0xff, 0x2f, 0x68, 0x0f, 0x06, 0x4d, 0x01, 0x00, 0x00,
-}
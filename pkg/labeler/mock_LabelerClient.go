@@ -17,6 +17,36 @@ type MockLabelerClient struct {
 	mock.Mock
 }
 
+// RemoveLabels provides a mock function with given fields: ctx, in, opts
+func (_m *MockLabelerClient) RemoveLabels(ctx context.Context, in *RemoveLabelsRequest, opts ...grpc.CallOption) (*RemoveLabelsReply, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *RemoveLabelsReply
+	if rf, ok := ret.Get(0).(func(context.Context, *RemoveLabelsRequest, ...grpc.CallOption) *RemoveLabelsReply); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*RemoveLabelsReply)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *RemoveLabelsRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SetLabels provides a mock function with given fields: ctx, in, opts
 func (_m *MockLabelerClient) SetLabels(ctx context.Context, in *SetLabelsRequest, opts ...grpc.CallOption) (*SetLabelsReply, error) {
 	_va := make([]interface{}, len(opts))
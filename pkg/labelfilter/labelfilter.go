@@ -0,0 +1,147 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package labelfilter implements the checks nfd-master uses for filtering
+// labeling requests, and the include/exclude filter nfd-worker applies to the
+// features of each source. The checks are shared between nfd-master and
+// nfd-worker so that the worker is able to explain why a label would not be
+// published.
+package labelfilter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CheckNamespace returns an error if the label has a namespace that is not
+// one of the allowed extra label namespaces. Labels without a namespace are
+// always allowed.
+func CheckNamespace(label string, extraLabelNs []string) error {
+	split := strings.SplitN(label, "/", 2)
+	if len(split) != 2 {
+		return nil
+	}
+	for _, ns := range extraLabelNs {
+		if split[0] == ns {
+			return nil
+		}
+	}
+	return fmt.Errorf("namespace %q is not allowed", split[0])
+}
+
+// CheckWhiteList returns an error if the name part of the label, i.e. the part
+// after the namespace, does not match the label whitelist
+func CheckWhiteList(label string, labelWhiteList *regexp.Regexp) error {
+	split := strings.SplitN(label, "/", 2)
+	name := split[len(split)-1]
+	if !labelWhiteList.MatchString(name) {
+		return fmt.Errorf("%q does not match the label whitelist %q", name, labelWhiteList.String())
+	}
+	return nil
+}
+
+// Config is the configuration of a Filter. If Include is non-empty, only
+// names matching at least one of its patterns pass the filter. Names matching
+// any of the Exclude patterns never pass.
+type Config struct {
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// Validate returns an error for each invalid pattern of the config
+func (c Config) Validate() []error {
+	errs := []error{}
+	for _, p := range c.Include {
+		if _, err := regexp.Compile(p); err != nil {
+			errs = append(errs, fmt.Errorf("invalid include pattern: %v", err))
+		}
+	}
+	for _, p := range c.Exclude {
+		if _, err := regexp.Compile(p); err != nil {
+			errs = append(errs, fmt.Errorf("invalid exclude pattern: %v", err))
+		}
+	}
+	return errs
+}
+
+// Filter includes and excludes names based on regular expressions
+type Filter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+	// invalid is the reason why the filter rejects all names, if set
+	invalid error
+}
+
+// New creates a new Filter from the given config
+func New(c Config) (*Filter, error) {
+	f := &Filter{}
+	var err error
+	if f.include, err = compile(c.Include); err != nil {
+		return nil, fmt.Errorf("invalid include pattern: %v", err)
+	}
+	if f.exclude, err = compile(c.Exclude); err != nil {
+		return nil, fmt.Errorf("invalid exclude pattern: %v", err)
+	}
+	return f, nil
+}
+
+// RejectAll creates a Filter that passes no names, e.g. to be used in place
+// of a filter with an invalid config
+func RejectAll(reason error) *Filter {
+	return &Filter{invalid: reason}
+}
+
+func compile(patterns []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// Check returns an error describing the reason if the name does not pass the
+// filter. A nil Filter passes all names.
+func (f *Filter) Check(name string) error {
+	if f == nil {
+		return nil
+	}
+	if f.invalid != nil {
+		return fmt.Errorf("%q is rejected by the invalid label filter: %v", name, f.invalid)
+	}
+	if len(f.include) > 0 {
+		included := false
+		for _, re := range f.include {
+			if re.MatchString(name) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return fmt.Errorf("%q does not match any include pattern of the label filter", name)
+		}
+	}
+	for _, re := range f.exclude {
+		if re.MatchString(name) {
+			return fmt.Errorf("%q matches the exclude pattern %q of the label filter", name, re.String())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package labelfilter
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestChecks(t *testing.T) {
+	Convey("When checking label namespaces", t, func() {
+		So(CheckNamespace("feature-1", nil), ShouldBeNil)
+		So(CheckNamespace("example.com/feature-1", []string{"example.com"}), ShouldBeNil)
+		So(CheckNamespace("example.com/feature-1", []string{"foo.com"}), ShouldNotBeNil)
+	})
+
+	Convey("When checking the label whitelist", t, func() {
+		re := regexp.MustCompile("^cpu-")
+		So(CheckWhiteList("example.com/cpu-avx", re), ShouldBeNil)
+		So(CheckWhiteList("kernel-version.major", re).Error(), ShouldEqual,
+			`"kernel-version.major" does not match the label whitelist "^cpu-"`)
+	})
+}
+
+func TestFilter(t *testing.T) {
+	Convey("When filtering names", t, func() {
+		Convey("a nil or empty filter passes everything", func() {
+			var nilFilter *Filter
+			So(nilFilter.Check("foo"), ShouldBeNil)
+
+			f, err := New(Config{})
+			So(err, ShouldBeNil)
+			So(f.Check("foo"), ShouldBeNil)
+		})
+
+		Convey("include patterns restrict the names that pass", func() {
+			f, err := New(Config{Include: []string{"^cpuid\\.AVX", "^hardware_multithreading$"}})
+			So(err, ShouldBeNil)
+			So(f.Check("cpuid.AVX512F"), ShouldBeNil)
+			So(f.Check("hardware_multithreading"), ShouldBeNil)
+			So(f.Check("cpuid.SSE4").Error(), ShouldEqual, `"cpuid.SSE4" does not match any include pattern of the label filter`)
+		})
+
+		Convey("exclude patterns take precedence over include patterns", func() {
+			f, err := New(Config{Include: []string{"^cpuid\\."}, Exclude: []string{"AVX512"}})
+			So(err, ShouldBeNil)
+			So(f.Check("cpuid.AVX"), ShouldBeNil)
+			So(f.Check("cpuid.AVX512F").Error(), ShouldEqual, `"cpuid.AVX512F" matches the exclude pattern "AVX512" of the label filter`)
+		})
+
+		Convey("invalid patterns are reported", func() {
+			_, err := New(Config{Include: []string{"("}})
+			So(err.Error(), ShouldStartWith, "invalid include pattern:")
+			_, err = New(Config{Exclude: []string{"["}})
+			So(err.Error(), ShouldStartWith, "invalid exclude pattern:")
+
+			errs := Config{Include: []string{"(", "ok"}, Exclude: []string{"["}}.Validate()
+			So(len(errs), ShouldEqual, 2)
+			So(errs[0].Error(), ShouldStartWith, "invalid include pattern:")
+			So(errs[1].Error(), ShouldStartWith, "invalid exclude pattern:")
+		})
+
+		Convey("a filter rejecting all names passes nothing", func() {
+			f := RejectAll(fmt.Errorf("broken"))
+			So(f.Check("foo").Error(), ShouldEqual, `"foo" is rejected by the invalid label filter: broken`)
+		})
+	})
+}
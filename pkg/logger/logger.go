@@ -0,0 +1,260 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logger implements structured, leveled logging for NFD. Log
+// messages consist of a message string and a list of key-value pairs. Info
+// messages have a verbosity level and are only printed if the configured
+// verbosity is high enough. All messages are written to stderr, either as
+// plain text or JSON.
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Supported log formats
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Severities of log messages
+const (
+	severityInfo    = "info"
+	severityWarning = "warning"
+	severityError   = "error"
+)
+
+// Global logging configuration
+var (
+	lock      sync.Mutex
+	output    io.Writer = os.Stderr
+	format              = FormatText
+	verbosity           = 0
+	// defaultValues are the key-value pairs added to the messages of all
+	// loggers
+	defaultValues []interface{}
+)
+
+// Configure sets the verbosity level and output format of logging. An empty
+// format means the default (text) format.
+func Configure(v int, f string) error {
+	switch f {
+	case "":
+		f = FormatText
+	case FormatText, FormatJSON:
+	default:
+		return fmt.Errorf("unsupported log format %q, must be one of %q or %q", f, FormatText, FormatJSON)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	verbosity = v
+	format = f
+	return nil
+}
+
+// SetOutput changes the destination of log messages
+func SetOutput(w io.Writer) {
+	lock.Lock()
+	defer lock.Unlock()
+	output = w
+}
+
+// SetDefaultValues sets the key-value pairs added to the messages of all
+// loggers, including the ones created before calling SetDefaultValues. The
+// values are written before the values of the logger itself.
+func SetDefaultValues(keysAndValues ...interface{}) {
+	lock.Lock()
+	defer lock.Unlock()
+	defaultValues = append([]interface{}{}, keysAndValues...)
+}
+
+// Logger writes log messages, adding a fixed set of key-value pairs to each of
+// them
+type Logger struct {
+	values []interface{}
+}
+
+// Verbose is a Logger that only writes info messages if the configured
+// verbosity is high enough
+type Verbose struct {
+	logger  Logger
+	level   int
+	enabled bool
+}
+
+// WithValues returns a Logger that adds the given key-value pairs to all
+// messages
+func WithValues(keysAndValues ...interface{}) Logger {
+	return Logger{}.WithValues(keysAndValues...)
+}
+
+// Info writes an info message with verbosity level 0
+func Info(msg string, keysAndValues ...interface{}) {
+	Logger{}.Info(msg, keysAndValues...)
+}
+
+// Warning writes a warning message
+func Warning(msg string, keysAndValues ...interface{}) {
+	Logger{}.Warning(msg, keysAndValues...)
+}
+
+// Error writes an error message
+func Error(err error, msg string, keysAndValues ...interface{}) {
+	Logger{}.Error(err, msg, keysAndValues...)
+}
+
+// V returns a Verbose logger for the given verbosity level
+func V(level int) Verbose {
+	return Logger{}.V(level)
+}
+
+// WithValues returns a copy of the Logger that adds the given key-value pairs
+// to all messages
+func (l Logger) WithValues(keysAndValues ...interface{}) Logger {
+	values := make([]interface{}, 0, len(l.values)+len(keysAndValues))
+	values = append(values, l.values...)
+	values = append(values, keysAndValues...)
+	return Logger{values: values}
+}
+
+// Info writes an info message with verbosity level 0
+func (l Logger) Info(msg string, keysAndValues ...interface{}) {
+	l.write(severityInfo, 0, msg, nil, keysAndValues)
+}
+
+// Warning writes a warning message
+func (l Logger) Warning(msg string, keysAndValues ...interface{}) {
+	l.write(severityWarning, 0, msg, nil, keysAndValues)
+}
+
+// Error writes an error message
+func (l Logger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.write(severityError, 0, msg, err, keysAndValues)
+}
+
+// V returns a Verbose logger for the given verbosity level
+func (l Logger) V(level int) Verbose {
+	lock.Lock()
+	defer lock.Unlock()
+	return Verbose{logger: l, level: level, enabled: level <= verbosity}
+}
+
+// Enabled returns true if the verbosity level is enabled
+func (v Verbose) Enabled() bool {
+	return v.enabled
+}
+
+// Info writes an info message if the verbosity level is enabled
+func (v Verbose) Info(msg string, keysAndValues ...interface{}) {
+	if v.enabled {
+		v.logger.write(severityInfo, v.level, msg, nil, keysAndValues)
+	}
+}
+
+// write formats and writes one log message
+func (l Logger) write(severity string, level int, msg string, err error, keysAndValues []interface{}) {
+	now := time.Now()
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	kvs := make([]interface{}, 0, len(defaultValues)+len(l.values)+len(keysAndValues)+2)
+	kvs = append(kvs, defaultValues...)
+	kvs = append(kvs, l.values...)
+	kvs = append(kvs, keysAndValues...)
+	if err != nil {
+		kvs = append(kvs, "error", err)
+	}
+
+	var buf bytes.Buffer
+	if format == FormatJSON {
+		writeJSON(&buf, now, severity, level, msg, kvs)
+	} else {
+		writeText(&buf, now, severity, msg, kvs)
+	}
+	output.Write(buf.Bytes())
+}
+
+// writeText formats a log message as a single line of text, e.g.
+// 2020-06-01T12:00:00Z INFO discovered feature source="cpu" value=true
+func writeText(buf *bytes.Buffer, t time.Time, severity string, msg string, kvs []interface{}) {
+	fmt.Fprintf(buf, "%s %-7s %s", t.Format(time.RFC3339), strings.ToUpper(severity), msg)
+	for i := 0; i < len(kvs); i += 2 {
+		key, value := keyValue(kvs, i)
+		fmt.Fprintf(buf, " %s=%s", key, formatTextValue(value))
+	}
+	buf.WriteByte('\n')
+}
+
+// writeJSON formats a log message as a single-line JSON object
+func writeJSON(buf *bytes.Buffer, t time.Time, severity string, level int, msg string, kvs []interface{}) {
+	entry := map[string]interface{}{}
+	for i := 0; i < len(kvs); i += 2 {
+		key, value := keyValue(kvs, i)
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+		entry[key] = value
+	}
+	entry["time"] = t.Format(time.RFC3339Nano)
+	entry["level"] = severity
+	entry["msg"] = msg
+	if severity == severityInfo {
+		entry["v"] = level
+	}
+
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		// Fall back to stringifying all values
+		for k, v := range entry {
+			entry[k] = fmt.Sprintf("%v", v)
+		}
+		raw, _ = json.Marshal(entry)
+	}
+	buf.Write(raw)
+	buf.WriteByte('\n')
+}
+
+// keyValue returns the key-value pair starting at index i
+func keyValue(kvs []interface{}, i int) (string, interface{}) {
+	key := fmt.Sprintf("%v", kvs[i])
+	if i+1 >= len(kvs) {
+		return key, "(MISSING)"
+	}
+	return key, kvs[i+1]
+}
+
+func formatTextValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case error:
+		return strconv.Quote(v.Error())
+	case fmt.Stringer:
+		return strconv.Quote(v.String())
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLogger(t *testing.T) {
+	Convey("When logging", t, func() {
+		buf := &bytes.Buffer{}
+		SetOutput(buf)
+		defer SetOutput(os.Stderr)
+		defer Configure(0, "")
+
+		log := WithValues("node", "node-1")
+
+		Convey("When an unsupported format is configured", func() {
+			err := Configure(0, "xml")
+			Convey("an error should be returned", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When using text format", func() {
+			So(Configure(1, "text"), ShouldBeNil)
+			log.Info("feature discovered", "source", "cpu", "value", true)
+			log.Error(fmt.Errorf("boom"), "discovery failed")
+			log.Warning("missing value", "source")
+
+			Convey("messages should be written as lines of text", func() {
+				lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+				So(len(lines), ShouldEqual, 3)
+				So(lines[0], ShouldEndWith, `INFO    feature discovered node="node-1" source="cpu" value=true`)
+				So(lines[1], ShouldEndWith, `ERROR   discovery failed node="node-1" error="boom"`)
+				So(lines[2], ShouldEndWith, `WARNING missing value node="node-1" source="(MISSING)"`)
+			})
+		})
+
+		Convey("When using json format", func() {
+			So(Configure(0, "json"), ShouldBeNil)
+			log.WithValues("source", "kernel").Error(fmt.Errorf("boom"), "discovery failed")
+
+			Convey("messages should be written as JSON objects", func() {
+				entry := map[string]interface{}{}
+				So(json.Unmarshal(buf.Bytes(), &entry), ShouldBeNil)
+				So(entry["level"], ShouldEqual, "error")
+				So(entry["msg"], ShouldEqual, "discovery failed")
+				So(entry["node"], ShouldEqual, "node-1")
+				So(entry["source"], ShouldEqual, "kernel")
+				So(entry["error"], ShouldEqual, "boom")
+				So(entry["time"], ShouldNotBeEmpty)
+			})
+		})
+
+		Convey("When default values are set", func() {
+			SetDefaultValues("component", "test")
+			defer SetDefaultValues()
+			So(Configure(0, "text"), ShouldBeNil)
+			log.WithValues("source", "cpu").Info("feature discovered")
+			Info("started")
+
+			Convey("they should be added to the messages of all loggers", func() {
+				lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+				So(len(lines), ShouldEqual, 2)
+				So(lines[0], ShouldEndWith, `INFO    feature discovered component="test" node="node-1" source="cpu"`)
+				So(lines[1], ShouldEndWith, `INFO    started component="test"`)
+			})
+		})
+
+		Convey("When verbosity is limited", func() {
+			So(Configure(1, "text"), ShouldBeNil)
+			log.V(1).Info("visible")
+			log.V(2).Info("hidden")
+
+			Convey("only messages with a low enough level should be written", func() {
+				So(log.V(1).Enabled(), ShouldBeTrue)
+				So(log.V(2).Enabled(), ShouldBeFalse)
+				So(buf.String(), ShouldContainSubstring, "visible")
+				So(buf.String(), ShouldNotContainSubstring, "hidden")
+			})
+		})
+	})
+}
@@ -333,6 +333,30 @@ func TestSetLabels(t *testing.T) {
 			})
 		})
 
+		Convey("When source status is reported", func() {
+			mockHelper.On("GetClient").Return(mockClient, nil)
+			mockHelper.On("GetNode", mockClient, workerName).Return(mockNode, nil)
+			mockHelper.On("UpdateNode", mockClient, mockNode).Return(nil)
+			mockReq.SourceStatus = map[string]*labeler.SourceStatus{
+				"cpu":    {Status: "ok"},
+				"custom": {Status: "error", Message: "mock-error"},
+			}
+			_, err := mockServer.SetLabels(mockCtx, mockReq)
+			Convey("Error is nil", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("Node object should have the source status annotation", func() {
+				So(mockNode.Annotations[AnnotationNs+"source-status"], ShouldEqual,
+					`{"cpu":{"status":"ok"},"custom":{"status":"error","message":"mock-error"}}`)
+			})
+			Convey("the annotation should be removed when the status is not reported anymore", func() {
+				mockReq.SourceStatus = nil
+				_, err := mockServer.SetLabels(mockCtx, mockReq)
+				So(err, ShouldBeNil)
+				So(mockNode.Annotations, ShouldNotContainKey, AnnotationNs+"source-status")
+			})
+		})
+
 		mockErr := errors.New("mock-error")
 		Convey("When node update fails", func() {
 			mockHelper.On("GetClient").Return(mockClient, mockErr)
@@ -352,6 +376,56 @@ func TestSetLabels(t *testing.T) {
 	})
 }
 
+func TestRemoveLabels(t *testing.T) {
+	Convey("When servicing RemoveLabels request", t, func() {
+		const workerName = "mock-worker"
+		mockHelper := &apihelper.MockAPIHelpers{}
+		mockClient := &k8sclient.Clientset{}
+		mockNode := newMockNode()
+		mockNode.Labels[LabelNs+"feature-1"] = "val-1"
+		mockNode.Labels["other-label"] = "val"
+		mockNode.Annotations[AnnotationNs+"worker.version"] = "0.1-test"
+		mockNode.Annotations[AnnotationNs+"feature-labels"] = "feature-1"
+		mockNode.Annotations[AnnotationNs+"extended-resources"] = ""
+		mockNode.Annotations[AnnotationNs+"source-status"] = `{"cpu":{"status":"ok"}}`
+		mockNode.Annotations[AnnotationNs+"master.version"] = "0.1-test"
+		mockServer := labelerServer{args: Args{LabelWhiteList: regexp.MustCompile("")}, apiHelper: mockHelper}
+		mockReq := &labeler.RemoveLabelsRequest{NodeName: workerName, NfdVersion: "0.1-test"}
+
+		Convey("When node update succeeds", func() {
+			mockHelper.On("GetClient").Return(mockClient, nil)
+			mockHelper.On("GetNode", mockClient, workerName).Return(mockNode, nil)
+			mockHelper.On("UpdateNode", mockClient, mockNode).Return(nil)
+			_, err := mockServer.RemoveLabels(context.Background(), mockReq)
+			Convey("No error should be returned", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("Only the labels and annotations of the worker should be removed", func() {
+				So(mockNode.Labels, ShouldResemble, map[string]string{"other-label": "val"})
+				So(mockNode.Annotations, ShouldResemble, map[string]string{AnnotationNs + "master.version": "0.1-test"})
+			})
+		})
+
+		Convey("When node update fails", func() {
+			mockErr := errors.New("mock-error")
+			mockHelper.On("GetClient").Return(mockClient, mockErr)
+			_, err := mockServer.RemoveLabels(context.Background(), mockReq)
+			Convey("An error should be returned", func() {
+				So(err, ShouldEqual, mockErr)
+			})
+		})
+
+		Convey("With '--no-publish'", func() {
+			mockServer.args.NoPublish = true
+			_, err := mockServer.RemoveLabels(context.Background(), mockReq)
+			Convey("Operation should succeed without touching the node", func() {
+				So(err, ShouldBeNil)
+				So(mockNode.Labels[LabelNs+"feature-1"], ShouldEqual, "val-1")
+			})
+		})
+	})
+}
+
 func TestAddLabels(t *testing.T) {
 	Convey("When adding labels", t, func() {
 		labels := map[string]string{}
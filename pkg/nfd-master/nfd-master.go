@@ -19,9 +19,9 @@ package nfdmaster
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"net"
 	"os"
 	"regexp"
@@ -37,6 +37,8 @@ import (
 	api "k8s.io/api/core/v1"
 	"sigs.k8s.io/node-feature-discovery/pkg/apihelper"
 	pb "sigs.k8s.io/node-feature-discovery/pkg/labeler"
+	"sigs.k8s.io/node-feature-discovery/pkg/labelfilter"
+	"sigs.k8s.io/node-feature-discovery/pkg/logger"
 	"sigs.k8s.io/node-feature-discovery/pkg/version"
 )
 
@@ -48,11 +50,10 @@ const (
 	AnnotationNs = "nfd.node.kubernetes.io/"
 )
 
-// package loggers
+// package logger
 var (
-	stdoutLogger = log.New(os.Stdout, "", log.LstdFlags)
-	stderrLogger = log.New(os.Stderr, "", log.LstdFlags)
-	nodeName     = os.Getenv("NODE_NAME")
+	nodeName = os.Getenv("NODE_NAME")
+	log      = logger.WithValues("component", "nfd-master")
 )
 
 // Labels are a Kubernetes representation of discovered features.
@@ -61,6 +62,14 @@ type Labels map[string]string
 // ExtendedResources are k8s extended resources which are created from discovered features.
 type ExtendedResources map[string]string
 
+// workerAnnotations are the annotations nfd-master maintains on behalf of
+// nfd-worker
+var workerAnnotations = []string{"worker.version", "feature-labels", "extended-resources", "source-status"}
+
+// optionalWorkerAnnotations are the worker annotations that are not always
+// advertised. They are removed from the node when not being updated.
+var optionalWorkerAnnotations = []string{"source-status"}
+
 // Annotations are used for NFD-related node metadata
 type Annotations map[string]string
 
@@ -77,6 +86,8 @@ type Args struct {
 	Prune          bool
 	VerifyNodeName bool
 	ResourceLabels []string
+	Verbosity      int
+	LogFormat      string
 }
 
 type NfdMaster interface {
@@ -111,6 +122,11 @@ func createStatusOp(verb string, resource string, path string, value string) sta
 func NewNfdMaster(args Args) (NfdMaster, error) {
 	nfd := &nfdMaster{args: args, ready: make(chan bool, 1)}
 
+	// Configure logging
+	if err := logger.Configure(args.Verbosity, args.LogFormat); err != nil {
+		return nfd, err
+	}
+
 	// Check TLS related args
 	if args.CertFile != "" || args.KeyFile != "" || args.CaFile != "" {
 		if args.CertFile == "" {
@@ -133,8 +149,7 @@ func NewNfdMaster(args Args) (NfdMaster, error) {
 // Run NfdMaster server. The method returns in case of fatal errors or if Stop()
 // is called.
 func (m *nfdMaster) Run() error {
-	stdoutLogger.Printf("Node Feature Discovery Master %s", version.Get())
-	stdoutLogger.Printf("NodeName: '%s'", nodeName)
+	log.Info("Node Feature Discovery Master", "version", version.Get(), "node", nodeName)
 
 	if m.args.Prune {
 		return m.prune()
@@ -184,7 +199,7 @@ func (m *nfdMaster) Run() error {
 	}
 	m.server = grpc.NewServer(serverOpts...)
 	pb.RegisterLabelerServer(m.server, &labelerServer{args: m.args, apiHelper: m.apihelper})
-	stdoutLogger.Printf("gRPC server serving on port: %d", m.args.Port)
+	log.Info("gRPC server serving", "port", m.args.Port)
 	return m.server.Serve(lis)
 }
 
@@ -221,7 +236,7 @@ func (m *nfdMaster) prune() error {
 	}
 
 	for _, node := range nodes.Items {
-		stdoutLogger.Printf("pruning node %q...", node.Name)
+		log.Info("pruning node", "node", node.Name)
 
 		// Prune labels and extended resources
 		err := updateNodeFeatures(m.apihelper, node.Name, Labels{}, Annotations{}, ExtendedResources{})
@@ -263,7 +278,7 @@ func updateMasterNode(helper apihelper.APIHelpers) error {
 	addAnnotations(node, Annotations{"master.version": version.Get()})
 	err = helper.UpdateNode(cli, node)
 	if err != nil {
-		stderrLogger.Printf("can't update node: %s", err.Error())
+		log.Error(err, "can't update node", "node", nodeName)
 		return err
 	}
 
@@ -273,26 +288,16 @@ func updateMasterNode(helper apihelper.APIHelpers) error {
 // Filter labels by namespace and name whitelist
 func filterFeatureLabels(labels Labels, extraLabelNs []string, labelWhiteList *regexp.Regexp, extendedResourceNames []string) (Labels, ExtendedResources) {
 	for label := range labels {
-		split := strings.SplitN(label, "/", 2)
-		name := split[0]
-
 		// Check namespaced labels, filter out if ns is not whitelisted
-		if len(split) == 2 {
-			ns := split[0]
-			name = split[1]
-			for i, extraNs := range extraLabelNs {
-				if ns == extraNs {
-					break
-				} else if i == len(extraLabelNs)-1 {
-					stderrLogger.Printf("Namespace '%s' is not allowed. Ignoring label '%s'\n", ns, label)
-					delete(labels, label)
-				}
-			}
+		if err := labelfilter.CheckNamespace(label, extraLabelNs); err != nil {
+			log.Warning("namespace is not allowed, ignoring label", "feature", label, "reason", err)
+			delete(labels, label)
+			continue
 		}
 
 		// Skip if label doesn't match labelWhiteList
-		if !labelWhiteList.MatchString(name) {
-			stderrLogger.Printf("%s does not match the whitelist (%s) and will not be published.", name, labelWhiteList.String())
+		if err := labelfilter.CheckWhiteList(label, labelWhiteList); err != nil {
+			log.V(1).Info("feature does not match the label whitelist and will not be published", "feature", label, "whitelist", labelWhiteList.String())
 			delete(labels, label)
 		}
 	}
@@ -304,7 +309,7 @@ func filterFeatureLabels(labels Labels, extraLabelNs []string, labelWhiteList *r
 		extendedResourceName = strings.TrimPrefix(extendedResourceName, LabelNs)
 		if _, ok := labels[extendedResourceName]; ok {
 			if _, err := strconv.Atoi(labels[extendedResourceName]); err != nil {
-				stderrLogger.Printf("bad label value encountered for extended resource: %s", err.Error())
+				log.Error(err, "bad label value encountered for extended resource", "feature", extendedResourceName)
 				continue // non-numeric label can't be used
 			}
 
@@ -324,30 +329,18 @@ type labelerServer struct {
 
 // Service SetLabels
 func (s *labelerServer) SetLabels(c context.Context, r *pb.SetLabelsRequest) (*pb.SetLabelsReply, error) {
-	if s.args.VerifyNodeName {
-		// Client authorization.
-		// Check that the node name matches the CN from the TLS cert
-		client, ok := peer.FromContext(c)
-		if !ok {
-			stderrLogger.Printf("gRPC request error: failed to get peer (client)")
-			return &pb.SetLabelsReply{}, fmt.Errorf("failed to get peer (client)")
-		}
-		tlsAuth, ok := client.AuthInfo.(credentials.TLSInfo)
-		if !ok {
-			stderrLogger.Printf("gRPC request error: incorrect client credentials from '%v'", client.Addr)
-			return &pb.SetLabelsReply{}, fmt.Errorf("incorrect client credentials")
-		}
-		if len(tlsAuth.State.VerifiedChains) == 0 || len(tlsAuth.State.VerifiedChains[0]) == 0 {
-			stderrLogger.Printf("gRPC request error: client certificate verification for '%v' failed", client.Addr)
-			return &pb.SetLabelsReply{}, fmt.Errorf("client certificate verification failed")
-		}
-		cn := tlsAuth.State.VerifiedChains[0][0].Subject.CommonName
-		if cn != r.NodeName {
-			stderrLogger.Printf("gRPC request error: authorization for %v failed: cert valid for '%s', requested node name '%s'", client.Addr, cn, r.NodeName)
-			return &pb.SetLabelsReply{}, fmt.Errorf("request authorization failed: cert valid for '%s', requested node name '%s'", cn, r.NodeName)
+	log := log.WithValues("node", r.NodeName)
+	if err := s.authorize(c, r.NodeName); err != nil {
+		log.Error(err, "gRPC request error")
+		return &pb.SetLabelsReply{}, err
+	}
+	log.Info("labeling request received", "nfdVersion", r.NfdVersion, "labels", len(r.Labels))
+	for name, status := range r.SourceStatus {
+		if status.Status != "ok" {
+			log.Warning("feature source of the worker is unhealthy", "source", name, "status", status.Status, "reason", status.Message)
 		}
 	}
-	stdoutLogger.Printf("REQUEST Node: %s NFD-version: %s Labels: %s", r.NodeName, r.NfdVersion, r.Labels)
+	log.V(2).Info("requested labels", "labels", r.Labels)
 
 	labels, extendedResources := filterFeatureLabels(r.Labels, s.args.ExtraLabelNs, s.args.LabelWhiteList, s.args.ResourceLabels)
 
@@ -370,15 +363,92 @@ func (s *labelerServer) SetLabels(c context.Context, r *pb.SetLabelsRequest) (*p
 			"extended-resources": strings.Join(extendedResourceKeys, ","),
 		}
 
+		// Advertise the health status of the feature sources of the worker.
+		// A stale status is removed if the worker does not report it.
+		if len(r.SourceStatus) > 0 {
+			status, err := json.Marshal(r.SourceStatus)
+			if err != nil {
+				log.Error(err, "failed to encode source status")
+				return &pb.SetLabelsReply{}, err
+			}
+			annotations["source-status"] = string(status)
+		}
+
 		err := updateNodeFeatures(s.apiHelper, r.NodeName, labels, annotations, extendedResources)
 		if err != nil {
-			stderrLogger.Printf("failed to advertise labels: %s", err.Error())
+			log.Error(err, "failed to advertise labels")
 			return &pb.SetLabelsReply{}, err
 		}
 	}
 	return &pb.SetLabelsReply{}, nil
 }
 
+// Service RemoveLabels
+func (s *labelerServer) RemoveLabels(c context.Context, r *pb.RemoveLabelsRequest) (*pb.RemoveLabelsReply, error) {
+	log := log.WithValues("node", r.NodeName)
+	if err := s.authorize(c, r.NodeName); err != nil {
+		log.Error(err, "gRPC request error")
+		return &pb.RemoveLabelsReply{}, err
+	}
+	log.Info("label removal request received", "nfdVersion", r.NfdVersion)
+
+	if !s.args.NoPublish {
+		err := removeNodeFeatures(s.apiHelper, r.NodeName)
+		if err != nil {
+			log.Error(err, "failed to remove labels")
+			return &pb.RemoveLabelsReply{}, err
+		}
+	}
+	return &pb.RemoveLabelsReply{}, nil
+}
+
+// authorize checks that the client is allowed to modify the given node, i.e.
+// that the CN of its TLS certificate matches the node name, if node name
+// verification is enabled
+func (s *labelerServer) authorize(c context.Context, nodeName string) error {
+	if !s.args.VerifyNodeName {
+		return nil
+	}
+	client, ok := peer.FromContext(c)
+	if !ok {
+		return fmt.Errorf("failed to get peer (client)")
+	}
+	tlsAuth, ok := client.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return fmt.Errorf("incorrect client credentials from %s", client.Addr.String())
+	}
+	if len(tlsAuth.State.VerifiedChains) == 0 || len(tlsAuth.State.VerifiedChains[0]) == 0 {
+		return fmt.Errorf("client certificate verification failed for %s", client.Addr.String())
+	}
+	cn := tlsAuth.State.VerifiedChains[0][0].Subject.CommonName
+	if cn != nodeName {
+		return fmt.Errorf("request authorization failed: cert valid for '%s', requested node name '%s'", cn, nodeName)
+	}
+	return nil
+}
+
+// removeNodeFeatures removes the labels, extended resources and annotations
+// published on behalf of nfd-worker from a node
+func removeNodeFeatures(helper apihelper.APIHelpers, nodeName string) error {
+	err := updateNodeFeatures(helper, nodeName, Labels{}, Annotations{}, ExtendedResources{})
+	if err != nil {
+		return err
+	}
+
+	cli, err := helper.GetClient()
+	if err != nil {
+		return err
+	}
+	node, err := helper.GetNode(cli, nodeName)
+	if err != nil {
+		return err
+	}
+	for _, a := range workerAnnotations {
+		delete(node.Annotations, AnnotationNs+a)
+	}
+	return helper.UpdateNode(cli, node)
+}
+
 // updateNodeFeatures ensures the Kubernetes node object is up to date,
 // creating new labels and extended resources where necessary and removing
 // outdated ones. Also updates the corresponding annotations.
@@ -410,13 +480,18 @@ func updateNodeFeatures(helper apihelper.APIHelpers, nodeName string, labels Lab
 	// Add labels to the node object.
 	addLabels(node, labels)
 
-	// Add annotations
+	// Remove optional annotations not advertised anymore, and add annotations
+	for _, a := range optionalWorkerAnnotations {
+		if _, ok := annotations[a]; !ok {
+			delete(node.Annotations, AnnotationNs+a)
+		}
+	}
 	addAnnotations(node, annotations)
 
 	// Send the updated node to the apiserver.
 	err = helper.UpdateNode(cli, node)
 	if err != nil {
-		stderrLogger.Printf("can't update node: %s", err.Error())
+		log.Error(err, "can't update node", "node", nodeName)
 		return err
 	}
 
@@ -424,7 +499,7 @@ func updateNodeFeatures(helper apihelper.APIHelpers, nodeName string, labels Lab
 	if len(statusOps) > 0 {
 		err = helper.PatchStatus(cli, node.Name, statusOps)
 		if err != nil {
-			stderrLogger.Printf("error while patching extended resources: %s", err.Error())
+			log.Error(err, "error while patching extended resources", "node", nodeName)
 			return err
 		}
 	}
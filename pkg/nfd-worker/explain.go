@@ -0,0 +1,203 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdworker
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"text/tabwriter"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/labelfilter"
+	"sigs.k8s.io/node-feature-discovery/source"
+)
+
+// Stages of the labeling pipeline where a feature may be dropped
+const (
+	stageSource = "source"
+	stageWorker = "worker"
+	stageMaster = "master"
+)
+
+// explanation describes the fate of one candidate feature
+type explanation struct {
+	Source  string
+	Feature string
+	Label   string
+	Value   string
+	// Stage where the feature was dropped, empty if it was published
+	Stage  string
+	Reason string
+}
+
+// explain runs feature discovery once and writes the decision made about each
+// candidate feature to out
+func (w *nfdWorker) explain(out io.Writer) error {
+	masterWhiteList, err := regexp.Compile(w.args.MasterLabelWhiteList)
+	if err != nil {
+		return fmt.Errorf("error parsing master label whitelist regex (%s): %s", w.args.MasterLabelWhiteList, err)
+	}
+
+	w.configure(w.args.ConfigFile, w.args.Options)
+
+	explanations := []explanation{}
+	sources, filters := w.getSourcesAndFilters()
+	for _, s := range sources {
+		explanations = append(explanations, explainSource(s, filters[s.Name()], w.labelWhiteList)...)
+	}
+	explainOverrides(explanations)
+	explainMaster(explanations, w.args.MasterExtraLabelNs, masterWhiteList)
+
+	if w.args.ExplainFeature != "" {
+		filtered := []explanation{}
+		for _, e := range explanations {
+			if e.Feature == w.args.ExplainFeature || e.Label == w.args.ExplainFeature {
+				filtered = append(filtered, e)
+			}
+		}
+		if len(filtered) == 0 {
+			return fmt.Errorf("feature %q was not discovered by any source", w.args.ExplainFeature)
+		}
+		explanations = filtered
+	}
+
+	return writeExplanations(out, explanations)
+}
+
+// explainSource runs feature discovery on one source and explains the
+// decisions made by the source itself and by the worker
+func explainSource(s source.FeatureSource, filter *labelfilter.Filter, labelWhiteList *regexp.Regexp) []explanation {
+	explanations := []explanation{}
+
+	result := discoverSource(s, filter, labelWhiteList)
+	if result.Err != nil {
+		return append(explanations, explanation{
+			Source:  s.Name(),
+			Feature: "*",
+			Stage:   stageSource,
+			Reason:  fmt.Sprintf("discovery failed: %v", result.Err),
+		})
+	}
+
+	if e, ok := s.(source.FeatureExplainer); ok {
+		dropped, err := e.DroppedFeatures()
+		if err != nil {
+			log.Error(err, "failed to get dropped features", "source", s.Name())
+		}
+		for _, d := range dropped {
+			explanations = append(explanations, explanation{
+				Source:  s.Name(),
+				Feature: d.Name,
+				Stage:   stageSource,
+				Reason:  d.Reason,
+			})
+		}
+	}
+
+	prefix := labelPrefix(s)
+	for name, v := range result.Features {
+		e := explanation{Source: s.Name(), Feature: name}
+		if err := filter.Check(name); err != nil {
+			e.Stage = stageWorker
+			e.Reason = err.Error()
+			explanations = append(explanations, e)
+			continue
+		}
+		var err error
+		e.Label, e.Value, err = featureLabel(prefix, name, v, labelWhiteList)
+		if err != nil {
+			e.Stage = stageWorker
+			e.Reason = err.Error()
+		}
+		explanations = append(explanations, e)
+	}
+
+	sort.Slice(explanations, func(i, j int) bool {
+		return explanations[i].Feature < explanations[j].Feature
+	})
+	return explanations
+}
+
+// explainOverrides marks labels that are overridden by another source when
+// the labels of all sources are merged
+func explainOverrides(explanations []explanation) {
+	sources := SourceLabels{}
+	for _, e := range explanations {
+		if e.Stage == "" {
+			if _, ok := sources[e.Source]; !ok {
+				sources[e.Source] = Labels{}
+			}
+			sources[e.Source][e.Label] = e.Value
+		}
+	}
+
+	// The last source in merge order wins
+	winner := map[string]string{}
+	for _, name := range sources.sourceNames() {
+		for label := range sources[name] {
+			winner[label] = name
+		}
+	}
+
+	for i, e := range explanations {
+		if e.Stage == "" && winner[e.Label] != e.Source {
+			explanations[i].Stage = stageWorker
+			explanations[i].Reason = fmt.Sprintf("overridden by source %q", winner[e.Label])
+		}
+	}
+}
+
+// explainMaster marks labels that nfd-master would drop
+func explainMaster(explanations []explanation, extraLabelNs []string, labelWhiteList *regexp.Regexp) {
+	for i, e := range explanations {
+		if e.Stage != "" {
+			continue
+		}
+		err := labelfilter.CheckNamespace(e.Label, extraLabelNs)
+		if err == nil {
+			err = labelfilter.CheckWhiteList(e.Label, labelWhiteList)
+		}
+		if err != nil {
+			explanations[i].Stage = stageMaster
+			explanations[i].Reason = err.Error()
+		}
+	}
+}
+
+// writeExplanations writes explanations as a table
+func writeExplanations(out io.Writer, explanations []explanation) error {
+	tw := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "SOURCE\tFEATURE\tLABEL\tVALUE\tDECISION\tREASON")
+	for _, e := range explanations {
+		decision := "published"
+		reason := "-"
+		if e.Stage != "" {
+			decision = "dropped by " + e.Stage
+			reason = e.Reason
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", e.Source, e.Feature, orDash(e.Label), orDash(e.Value), decision, reason)
+	}
+	return tw.Flush()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
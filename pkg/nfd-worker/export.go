@@ -0,0 +1,185 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdworker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Supported export formats
+const (
+	ExportFormatJSON = "json"
+	ExportFormatYAML = "yaml"
+	ExportFormatEnv  = "env"
+)
+
+// exportStdout is the --export-file value that makes the worker write to
+// stdout
+const exportStdout = "-"
+
+// envKeyPrefix is prepended to all variable names in the env export format
+const envKeyPrefix = "NFD_"
+
+var envKeyInvalidChars = regexp.MustCompile("[^A-Z0-9_]")
+
+// validateExportFormat checks that the given export format is supported. An
+// empty format means that exporting is disabled.
+func validateExportFormat(format string) error {
+	switch format {
+	case "", ExportFormatJSON, ExportFormatYAML, ExportFormatEnv:
+		return nil
+	}
+	return fmt.Errorf("unsupported export format %q, must be one of %q, %q or %q",
+		format, ExportFormatJSON, ExportFormatYAML, ExportFormatEnv)
+}
+
+// exportFeatureLabels writes the feature labels to the configured export
+// file, or to stdout.
+func (w *nfdWorker) exportFeatureLabels(labels SourceLabels) error {
+	var buf bytes.Buffer
+	if err := writeFeatureLabels(&buf, w.args.ExportFormat, labels, w.args.ExportGroupBySource); err != nil {
+		return err
+	}
+
+	if w.args.ExportFile == "" || w.args.ExportFile == exportStdout {
+		_, err := io.Copy(os.Stdout, &buf)
+		return err
+	}
+
+	// Write into a temporary file first so that readers never see a
+	// partially written export
+	dir := filepath.Dir(w.args.ExportFile)
+	f, err := ioutil.TempFile(dir, "."+filepath.Base(w.args.ExportFile)+".")
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %v", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write export file: %v", err)
+	}
+	if err := f.Chmod(0644); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write export file: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write export file: %v", err)
+	}
+	return os.Rename(f.Name(), w.args.ExportFile)
+}
+
+// writeFeatureLabels serializes feature labels in the given format. If
+// groupBySource is false the labels of all sources are merged into one flat
+// set.
+func writeFeatureLabels(out io.Writer, format string, labels SourceLabels, groupBySource bool) error {
+	var data interface{} = labels
+	if !groupBySource {
+		data = labels.merge()
+	}
+
+	switch format {
+	case ExportFormatJSON:
+		raw, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "%s\n", raw)
+		return err
+	case ExportFormatYAML:
+		raw, err := yaml.Marshal(data)
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(raw)
+		return err
+	case ExportFormatEnv:
+		// Variable names must be unambiguous over all sources as the output
+		// is meant to be sourced as one file
+		seen := map[string]string{}
+		if !groupBySource {
+			return writeEnv(out, labels.merge(), seen)
+		}
+		for _, name := range labels.sourceNames() {
+			if _, err := fmt.Fprintf(out, "# %s\n", name); err != nil {
+				return err
+			}
+			if err := writeEnv(out, labels[name], seen); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return validateExportFormat(format)
+}
+
+// writeEnv writes labels as KEY=VALUE lines, sorted by key. Seen maps the
+// variable names already written to the label they were derived from. An
+// error is returned if different labels map to the same variable name, the
+// same label repeated by another source is an intended override.
+func writeEnv(out io.Writer, labels Labels, seen map[string]string) error {
+	names := make([]string, 0, len(labels))
+	for k := range labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	vars := make(map[string]string, len(labels))
+	collisions := []string{}
+	for _, k := range names {
+		key := envKey(k)
+		if prev, ok := seen[key]; ok && prev != k {
+			collisions = append(collisions, fmt.Sprintf("%q and %q map to %s", prev, k, key))
+			continue
+		}
+		seen[key] = k
+		vars[key] = labels[k]
+	}
+	if len(collisions) > 0 {
+		return fmt.Errorf("conflicting environment variable names: %s", strings.Join(collisions, ", "))
+	}
+
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if _, err := fmt.Fprintf(out, "%s=%s\n", k, vars[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// envKey turns a label name into a valid environment variable name,
+// e.g. "cpu-cpuid.AVX" becomes "NFD_CPU_CPUID_AVX"
+func envKey(label string) string {
+	return envKeyPrefix + envKeyInvalidChars.ReplaceAllString(strings.ToUpper(label), "_")
+}
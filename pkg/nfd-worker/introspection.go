@@ -0,0 +1,139 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdworker
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/node-feature-discovery/source"
+)
+
+// publishResult describes the outcome of the latest labeling request sent to
+// nfd-master
+type publishResult struct {
+	Time   time.Time `json:"time"`
+	Labels Labels    `json:"labels"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// workerState holds the state of the worker exposed by the introspection API
+type workerState struct {
+	sync.RWMutex
+	config      NFDConfig
+	results     discoveryResults
+	lastPublish *publishResult
+}
+
+func newWorkerState() *workerState {
+	return &workerState{results: discoveryResults{}}
+}
+
+func (s *workerState) setConfig(c NFDConfig) {
+	s.Lock()
+	defer s.Unlock()
+	s.config = c
+}
+
+func (s *workerState) setDiscoveryResults(r discoveryResults) {
+	s.Lock()
+	defer s.Unlock()
+	s.results = r
+}
+
+func (s *workerState) setPublishResult(labels Labels, err error) {
+	s.Lock()
+	defer s.Unlock()
+	s.lastPublish = &publishResult{Time: time.Now(), Labels: labels}
+	if err != nil {
+		s.lastPublish.Error = err.Error()
+	}
+}
+
+// startIntrospectionServer starts serving the introspection API on the given
+// address. The server runs in the background for the lifetime of the process.
+func (w *nfdWorker) startIntrospectionServer(addr string) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	log.Info("introspection API serving", "address", lis.Addr().String())
+
+	go func() {
+		err := http.Serve(lis, w.state.handler())
+		log.Error(err, "introspection API stopped")
+	}()
+	return nil
+}
+
+// handler returns an http.Handler serving the introspection API
+func (s *workerState) handler() http.Handler {
+	mux := http.NewServeMux()
+
+	// Raw features returned by each source in the latest discovery round
+	mux.HandleFunc("/features", func(rw http.ResponseWriter, r *http.Request) {
+		s.RLock()
+		features := make(map[string]source.Features, len(s.results))
+		for name, result := range s.results {
+			features[name] = result.Features
+		}
+		s.RUnlock()
+		writeJSON(rw, features)
+	})
+
+	// Effective configuration of the worker
+	mux.HandleFunc("/config", func(rw http.ResponseWriter, r *http.Request) {
+		s.RLock()
+		defer s.RUnlock()
+		writeJSON(rw, s.config)
+	})
+
+	// Result of the latest labeling request
+	mux.HandleFunc("/publish", func(rw http.ResponseWriter, r *http.Request) {
+		s.RLock()
+		defer s.RUnlock()
+		writeJSON(rw, s.lastPublish)
+	})
+
+	// Errors from sources that failed in the latest discovery round
+	mux.HandleFunc("/errors", func(rw http.ResponseWriter, r *http.Request) {
+		s.RLock()
+		errs := map[string]string{}
+		for name, result := range s.results {
+			if result.Err != nil {
+				errs[name] = result.Err.Error()
+			}
+		}
+		s.RUnlock()
+		writeJSON(rw, errs)
+	})
+
+	return mux
+}
+
+func writeJSON(rw http.ResponseWriter, data interface{}) {
+	raw, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Write(append(raw, '\n'))
+}
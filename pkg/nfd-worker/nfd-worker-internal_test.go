@@ -17,17 +17,30 @@ limitations under the License.
 package nfdworker
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	. "github.com/smartystreets/goconvey/convey"
 	"github.com/stretchr/testify/mock"
 	"github.com/vektra/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
 	"sigs.k8s.io/node-feature-discovery/pkg/labeler"
+	"sigs.k8s.io/node-feature-discovery/pkg/labelfilter"
+	"sigs.k8s.io/node-feature-discovery/pkg/plugin"
 	"sigs.k8s.io/node-feature-discovery/source"
 	"sigs.k8s.io/node-feature-discovery/source/cpu"
 	"sigs.k8s.io/node-feature-discovery/source/fake"
@@ -53,14 +66,17 @@ func TestDiscoveryWithMockSources(t *testing.T) {
 
 		Convey("When I successfully get the labels from the mock source", func() {
 			mockFeatureSource.On("Name").Return(fakeFeatureSourceName)
+			mockFeatureSource.On("GetConfig").Return(nil)
 			mockFeatureSource.On("Discover").Return(fakeFeatures, nil)
 
-			returnedLabels, err := getFeatureLabels(fakeFeatureSource, labelWhiteList)
+			result := discoverSource(fakeFeatureSource, nil, labelWhiteList)
+			returnedLabels, err := result.Labels, result.Err
 			Convey("Proper label is returned", func() {
 				So(returnedLabels, ShouldResemble, fakeFeatureLabels)
 			})
 			Convey("Error is nil", func() {
 				So(err, ShouldBeNil)
+				So(result.Status, ShouldEqual, SourceStatusOK)
 			})
 		})
 
@@ -68,12 +84,14 @@ func TestDiscoveryWithMockSources(t *testing.T) {
 			expectedError := errors.New("fake error")
 			mockFeatureSource.On("Discover").Return(nil, expectedError)
 
-			returnedLabels, err := getFeatureLabels(fakeFeatureSource, labelWhiteList)
+			result := discoverSource(fakeFeatureSource, nil, labelWhiteList)
+			returnedLabels, err := result.Labels, result.Err
 			Convey("No label is returned", func() {
 				So(returnedLabels, ShouldBeNil)
 			})
 			Convey("Error is produced", func() {
 				So(err, ShouldEqual, expectedError)
+				So(result.Status, ShouldEqual, SourceStatusError)
 			})
 		})
 	})
@@ -143,6 +161,42 @@ func TestConfigParse(t *testing.T) {
 			})
 		})
 
+		Convey("and host paths are specified", func() {
+			worker.args.HostPrefix = "/host-"
+			defer source.SetHostPaths(source.NewHostPaths(""))
+			worker.configure("non-existing-file", `{"hostPaths": {"proc": "/host/proc"}}`)
+
+			Convey("host directories should be resolved from the prefix and the config", func() {
+				So(source.SysfsDir, ShouldEqual, "/host-sys")
+				So(source.UsrLibDir, ShouldEqual, "/host-usr/lib")
+				So(source.ProcfsDir, ShouldEqual, "/host/proc")
+				So(worker.config.HostPaths, ShouldResemble, source.GetHostPaths())
+			})
+		})
+
+		Convey("and the enabled sources are specified", func() {
+			sourceNames := func() []string {
+				names := []string{}
+				for _, s := range worker.getSources() {
+					names = append(names, s.Name())
+				}
+				return names
+			}
+			worker.configure("non-existing-file", `{"core": {"sources": ["kernel", "fake", "local"]}, "sources": {"cpu": {"cpuid": {"attributeWhitelist": ["AVX"]}}}}`)
+
+			Convey("the sources should be enabled and disabled accordingly", func() {
+				So(sourceNames(), ShouldResemble, []string{"fake", "kernel", "local"})
+				So(worker.config.Core.Sources, ShouldResemble, []string{"kernel", "fake", "local"})
+			})
+			Convey("disabled sources should be configured, too", func() {
+				So(worker.config.Sources["cpu"].(*cpu.Config).Cpuid.AttributeWhitelist, ShouldResemble, []string{"AVX"})
+			})
+			Convey("the command line should take effect when the sources are not specified", func() {
+				worker.configure("non-existing-file", "")
+				So(sourceNames(), ShouldResemble, []string{"cpu", "kernel", "pci"})
+			})
+		})
+
 		Convey("and a proper config file and overrides are given", func() {
 			overrides := `{"sources": {"pci": {"deviceClassWhitelist": ["03"]}}}`
 			worker.configure(f.Name(), overrides)
@@ -217,14 +271,150 @@ func TestNewNfdWorker(t *testing.T) {
 	})
 }
 
-func TestCreateFeatureLabels(t *testing.T) {
+func TestValidateConfigData(t *testing.T) {
+	Convey("When validating configuration", t, func() {
+		sources := builtinSources()
+
+		Convey("valid configuration produces no errors", func() {
+			data, err := ioutil.ReadFile("../../nfd-worker.conf.example")
+			So(err, ShouldBeNil)
+			So(validateConfigData(data, sources), ShouldBeEmpty)
+			// Uncomment the example config
+			uncommented := regexp.MustCompile("(?m)^#").ReplaceAll(data, nil)
+			So(validateConfigData(uncommented, sources), ShouldBeEmpty)
+			So(validateConfigData([]byte(""), sources), ShouldBeEmpty)
+			So(validateConfigData([]byte(`{"sources": {"kernel": {"configOpts": ["NO_HZ"]}}}`), sources), ShouldBeEmpty)
+		})
+
+		Convey("unknown fields are reported", func() {
+			errs := validateConfigData([]byte(`
+core:
+  plugins: [foo]
+sources:
+  foo: {}
+  cpu:
+    cpuid:
+      attributeWhiteList: ["AVX"]
+  usb:
+    deviceClasses: ["0e"]
+`), sources)
+			So(len(errs), ShouldEqual, 2)
+			So(errs[0].Error(), ShouldEqual, `failed to parse "cpu" source config: unknown field "cpuid.attributeWhiteList"`)
+			So(errs[1].Error(), ShouldContainSubstring, `failed to parse "usb" source config`)
+		})
+
+		Convey("the config of plugins listed in core.plugins is not checked", func() {
+			data := []byte(`{"core": {"plugins": ["foo"]}, "sources": {"foo": {"any": "thing"}, "cpu": {}}}`)
+			So(validateConfigData(data, sources), ShouldBeEmpty)
+			So(unregisteredPlugins(data, sources), ShouldResemble, []string{"foo"})
+		})
+
+		Convey("unknown sources are reported", func() {
+			data := []byte(`
+core:
+  plugins: [foo, cpu]
+sources:
+  kernal:
+    configOpts: [NO_HZ]
+`)
+			errs := validateConfigData(data, sources)
+			So(len(errs), ShouldEqual, 2)
+			So(errs[0].Error(), ShouldEqual, `plugin name "cpu" in core.plugins conflicts with a built-in feature source`)
+			So(errs[1].Error(), ShouldEqual, `unknown feature source "kernal" in sources, plugins need to be listed in core.plugins`)
+			So(unregisteredPlugins(data, sources), ShouldBeEmpty)
+		})
+
+		Convey("semantic errors are reported", func() {
+			errs := validateConfigData([]byte(`
+sources:
+  cpu:
+    model:
+      labelFields: ["vendor_id", "model"]
+  pci:
+    deviceClassWhitelist: ["0300", "0x12"]
+    deviceLabelFields: ["vendor", "subsystem"]
+  custom:
+    - name: "my.feature"
+      matchOn:
+        - usbId:
+            vendor: ["1d6b"]
+            device: ["03"]
+`), sources)
+			So(len(errs), ShouldEqual, 4)
+			So(errs[0].Error(), ShouldEqual, `invalid "cpu" source config: invalid field "model" in model.labelFields, must be one of [vendor_id family id stepping]`)
+			So(errs[1].Error(), ShouldEqual, `invalid "custom" source config: feature "my.feature": matchOn[0]: invalid usbId.device "03", must be 4 lowercase hex digits`)
+			So(errs[2].Error(), ShouldContainSubstring, `invalid PCI device class "0x12"`)
+			So(errs[3].Error(), ShouldContainSubstring, `invalid field "subsystem" in deviceLabelFields`)
+		})
+
+		Convey("invalid sysctl keys and value mappings are reported", func() {
+			errs := validateConfigData([]byte(`
+sources:
+  kernel:
+    sysctlOpts:
+      - key: "vm.nr_hugepages"
+        values: {"0": "none", "1024": "1G!"}
+      - key: "../etc/passwd"
+  custom:
+    - name: "my.feature"
+      matchOn:
+        - sysctl:
+            "vm..swappiness": "0"
+`), sources)
+			So(len(errs), ShouldEqual, 3)
+			So(errs[0].Error(), ShouldEqual, `invalid "custom" source config: feature "my.feature": matchOn[0]: sysctl: invalid sysctl key "vm..swappiness"`)
+			So(errs[1].Error(), ShouldStartWith, `invalid "kernel" source config: sysctlOpts[0]: invalid label value "1G!" for value "1024"`)
+			So(errs[2].Error(), ShouldEqual, `invalid "kernel" source config: sysctlOpts[1]: invalid sysctl key "../etc/passwd"`)
+		})
+
+		Convey("invalid kernel module names are reported", func() {
+			errs := validateConfigData([]byte(`
+sources:
+  kernel:
+    moduleOpts: ["vfio-pci", "../vfio"]
+  custom:
+    - name: "my.feature"
+      matchOn:
+        - availableKMod: ["vfio_pci", "vfio pci"]
+`), sources)
+			So(len(errs), ShouldEqual, 2)
+			So(errs[0].Error(), ShouldEqual, `invalid "custom" source config: feature "my.feature": matchOn[0]: availableKMod: invalid kernel module name "vfio pci"`)
+			So(errs[1].Error(), ShouldEqual, `invalid "kernel" source config: moduleOpts: invalid kernel module name "../vfio"`)
+		})
+
+		Convey("invalid label filters are reported", func() {
+			errs := validateConfigData([]byte(`
+sources:
+  memory:
+    labelFilter:
+      include: ["numa"]
+      exclude: ["nv.("]
+`), sources)
+			So(len(errs), ShouldEqual, 1)
+			So(errs[0].Error(), ShouldStartWith, `invalid "memory" source config: labelFilter: invalid exclude pattern:`)
+		})
+
+		Convey("unknown sources in the core config are reported", func() {
+			errs := validateConfigData([]byte(`{"core": {"sources": ["cpu", "foo"], "Sources": ["bar"]}}`), sources)
+			So(len(errs), ShouldEqual, 2)
+			So(errs[0].Error(), ShouldEqual, `failed to parse core config: unknown field "Sources"`)
+			So(errs[1].Error(), ShouldEqual, `unknown feature source "foo" in core.sources`)
+		})
+
+		Convey("malformed data is reported", func() {
+			So(validateConfigData([]byte(`sources: [`), sources), ShouldHaveLength, 1)
+		})
+	})
+}
+
+func TestDiscoverFeatures(t *testing.T) {
 	Convey("When creating feature labels from the configured sources", t, func() {
 		Convey("When fake feature source is configured", func() {
 			emptyLabelWL, _ := regexp.Compile("")
 			fakeFeatureSource := source.FeatureSource(new(fake.Source))
 			sources := []source.FeatureSource{}
 			sources = append(sources, fakeFeatureSource)
-			labels := createFeatureLabels(sources, emptyLabelWL)
+			labels := discoverFeatures(sources, nil, emptyLabelWL, 0, nil).labels().merge()
 
 			Convey("Proper fake labels are returned", func() {
 				So(len(labels), ShouldEqual, 3)
@@ -238,7 +428,7 @@ func TestCreateFeatureLabels(t *testing.T) {
 			fakeFeatureSource := source.FeatureSource(new(fake.Source))
 			sources := []source.FeatureSource{}
 			sources = append(sources, fakeFeatureSource)
-			labels := createFeatureLabels(sources, emptyLabelWL)
+			labels := discoverFeatures(sources, nil, emptyLabelWL, 0, nil).labels().merge()
 
 			Convey("fake labels are not returned", func() {
 				So(len(labels), ShouldEqual, 0)
@@ -247,21 +437,525 @@ func TestCreateFeatureLabels(t *testing.T) {
 				So(labels, ShouldNotContainKey, "fake-fakefeature3")
 			})
 		})
+		Convey("When a feature source has a label filter", func() {
+			mockFeatureSource := new(source.MockFeatureSource)
+			mockFeatureSource.On("Name").Return("kernel")
+			mockFeatureSource.On("Discover").Return(source.Features{"config.NO_HZ": true, "config.PREEMPT": true, "version.major": "5"}, nil)
+			sources := []source.FeatureSource{mockFeatureSource}
+
+			Convey("only the features passing the filter are labeled", func() {
+				filters := newLabelFilters(sourcesConfig{"kernel": &kernel.Config{LabelFilterConfig: source.LabelFilterConfig{
+					LabelFilter: &labelfilter.Config{Include: []string{"^config\\."}, Exclude: []string{"NO_HZ$"}},
+				}}})
+				labels := discoverFeatures(sources, filters, regexp.MustCompile(""), 0, nil).labels().merge()
+				So(labels, ShouldResemble, Labels{"kernel-config.PREEMPT": "true"})
+			})
+			Convey("the filters of the custom source and of plugins are applied, too", func() {
+				w, err := NewNfdWorker(Args{Sources: []string{"custom"}})
+				So(err, ShouldBeNil)
+				worker := w.(*nfdWorker)
+				worker.configure("non-existing-file", `
+sources:
+  custom:
+    labelFilter:
+      exclude: ["^my"]
+    features:
+      - name: my.feature
+        matchOn:
+          - loadedKMod: ["kvm"]
+  fake_plugin:
+    labelFilter:
+      include: ["^foo$"]
+`)
+				_, filters := worker.getSourcesAndFilters()
+				So(filters["custom"].Check("my.feature"), ShouldNotBeNil)
+				So(filters["custom"].Check("rdma.capable"), ShouldBeNil)
+				So(filters["fake_plugin"].Check("foo"), ShouldBeNil)
+				So(filters["fake_plugin"].Check("bar"), ShouldNotBeNil)
+			})
+			Convey("no features are labeled if the filter is invalid", func() {
+				filters := newLabelFilters(sourcesConfig{"kernel": &kernel.Config{LabelFilterConfig: source.LabelFilterConfig{
+					LabelFilter: &labelfilter.Config{Exclude: []string{"("}},
+				}}})
+				labels := discoverFeatures(sources, filters, regexp.MustCompile(""), 0, nil).labels().merge()
+				So(labels, ShouldBeEmpty)
+			})
+		})
+		Convey("When a feature source does not complete discovery in time", func() {
+			mockFeatureSource := new(source.MockFeatureSource)
+			mockFeatureSource.On("Name").Return("slow")
+			mockFeatureSource.On("GetConfig").Return(nil)
+			mockFeatureSource.On("Discover").After(time.Second).Return(source.Features{"feature": true}, nil)
+			sources := []source.FeatureSource{mockFeatureSource, new(fake.Source)}
+			results := discoverFeatures(sources, nil, regexp.MustCompile(""), 10*time.Millisecond, nil)
+
+			Convey("the source is reported as timed out", func() {
+				status := results.status()
+				So(status["slow"].Status, ShouldEqual, SourceStatusTimeout)
+				So(status["slow"].Message, ShouldNotBeEmpty)
+				So(status["fake"], ShouldResemble, &labeler.SourceStatus{Status: SourceStatusOK})
+				So(results.labels(), ShouldNotContainKey, "slow")
+			})
+			Convey("the source is skipped and not reconfigured until discovery completes", func() {
+				mockFeatureSource.On("SetConfig", "new").Return()
+				running.setConfig(mockFeatureSource, "new")
+				results := discoverFeatures(sources, nil, regexp.MustCompile(""), 10*time.Millisecond, nil)
+				So(results.status()["slow"].Status, ShouldEqual, SourceStatusTimeout)
+				mockFeatureSource.AssertNumberOfCalls(t, "Discover", 1)
+				mockFeatureSource.AssertNotCalled(t, "SetConfig", "new")
+
+				// Wait for the background discovery to complete
+				deadline := time.Now().Add(5 * time.Second)
+				for !running.start(mockFeatureSource) && time.Now().Before(deadline) {
+					time.Sleep(10 * time.Millisecond)
+				}
+				running.done(mockFeatureSource)
+				mockFeatureSource.AssertCalled(t, "SetConfig", "new")
+			})
+		})
+		Convey("When discovery is stopped", func() {
+			mockFeatureSource := new(source.MockFeatureSource)
+			mockFeatureSource.On("Name").Return("slow")
+			mockFeatureSource.On("Discover").After(100*time.Millisecond).Return(source.Features{"feature": true}, nil)
+			sources := []source.FeatureSource{mockFeatureSource, new(fake.Source)}
+			stop := make(chan struct{})
+
+			Convey("the remaining sources are skipped", func() {
+				close(stop)
+				So(discoverFeatures(sources, nil, regexp.MustCompile(""), 0, stop), ShouldBeEmpty)
+			})
+			Convey("the running source is abandoned and can be waited for", func() {
+				time.AfterFunc(10*time.Millisecond, func() { close(stop) })
+				results := discoverFeatures(sources, nil, regexp.MustCompile(""), time.Minute, stop)
+				So(results, ShouldContainKey, "slow")
+				So(results, ShouldNotContainKey, "fake")
+				So(results.status()["slow"].Message, ShouldEqual, "discovery stopped")
+				So(running.wait(5*time.Second), ShouldBeTrue)
+				mockFeatureSource.AssertNumberOfCalls(t, "Discover", 1)
+			})
+		})
 	})
 }
 
-func TestGetFeatureLabels(t *testing.T) {
+func TestDiscoverSource(t *testing.T) {
 	Convey("When I get feature labels and panic occurs during discovery of a feature source", t, func() {
 		fakePanicFeatureSource := source.FeatureSource(new(panicfake.Source))
 
-		returnedLabels, err := getFeatureLabels(fakePanicFeatureSource, regexp.MustCompile(""))
+		result := discoverSource(fakePanicFeatureSource, nil, regexp.MustCompile(""))
+		returnedLabels, err := result.Labels, result.Err
 		Convey("No label is returned", func() {
 			So(len(returnedLabels), ShouldEqual, 0)
 		})
 		Convey("Error is produced and panic error is returned", func() {
 			So(err, ShouldResemble, fmt.Errorf("fake panic error"))
+			So(result.Status, ShouldEqual, SourceStatusPanic)
+		})
+
+	})
+}
+
+func TestWriteFeatureLabels(t *testing.T) {
+	Convey("When exporting feature labels", t, func() {
+		labels := SourceLabels{
+			"local": Labels{"cpu-foo": "override", "example.com/bar": "1"},
+			"cpu":   Labels{"cpu-foo": "true", "cpu-cpuid.AVX": "true"},
+		}
+		var buf bytes.Buffer
+
+		Convey("merged labels are exported as json", func() {
+			err := writeFeatureLabels(&buf, ExportFormatJSON, labels, false)
+			So(err, ShouldBeNil)
+			So(buf.String(), ShouldEqual, `{
+  "cpu-cpuid.AVX": "true",
+  "cpu-foo": "override",
+  "example.com/bar": "1"
+}
+`)
+		})
+		Convey("grouped labels are exported as yaml", func() {
+			err := writeFeatureLabels(&buf, ExportFormatYAML, labels, true)
+			So(err, ShouldBeNil)
+			So(buf.String(), ShouldEqual, `cpu:
+  cpu-cpuid.AVX: "true"
+  cpu-foo: "true"
+local:
+  cpu-foo: override
+  example.com/bar: "1"
+`)
+		})
+		Convey("merged labels are exported as env", func() {
+			err := writeFeatureLabels(&buf, ExportFormatEnv, labels, false)
+			So(err, ShouldBeNil)
+			So(buf.String(), ShouldEqual, "NFD_CPU_CPUID_AVX=true\nNFD_CPU_FOO=override\nNFD_EXAMPLE_COM_BAR=1\n")
+		})
+		Convey("grouped labels are exported as env", func() {
+			err := writeFeatureLabels(&buf, ExportFormatEnv, labels, true)
+			So(err, ShouldBeNil)
+			So(buf.String(), ShouldEqual, "# cpu\nNFD_CPU_CPUID_AVX=true\nNFD_CPU_FOO=true\n# local\nNFD_CPU_FOO=override\nNFD_EXAMPLE_COM_BAR=1\n")
 		})
+		Convey("colliding env variable names are rejected", func() {
+			labels["cpu"]["cpu-foo.bar"] = "true"
+			labels["local"]["cpu-foo-bar"] = "true"
+			err := writeFeatureLabels(&buf, ExportFormatEnv, labels, false)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "NFD_CPU_FOO_BAR")
+			err = writeFeatureLabels(&buf, ExportFormatEnv, labels, true)
+			So(err, ShouldNotBeNil)
+		})
+		Convey("an unknown format is rejected", func() {
+			err := writeFeatureLabels(&buf, "xml", labels, false)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
 
+func TestIntrospection(t *testing.T) {
+	Convey("When querying the introspection API", t, func() {
+		state := newWorkerState()
+		state.setConfig(NFDConfig{Sources: sourcesConfig{"kernel": &kernel.Config{ConfigOpts: []string{"DMI"}}}})
+		state.setDiscoveryResults(discoveryResults{
+			"fake":       discoverSource(new(fake.Source), nil, regexp.MustCompile("")),
+			"panic_fake": discoverSource(new(panicfake.Source), nil, regexp.MustCompile("")),
+		})
+		server := httptest.NewServer(state.handler())
+		defer server.Close()
+
+		get := func(path string) map[string]interface{} {
+			resp, err := http.Get(server.URL + path)
+			So(err, ShouldBeNil)
+			defer resp.Body.Close()
+			So(resp.StatusCode, ShouldEqual, http.StatusOK)
+			data := map[string]interface{}{}
+			So(json.NewDecoder(resp.Body).Decode(&data), ShouldBeNil)
+			return data
+		}
+
+		Convey("raw features of each source are returned", func() {
+			features := get("/features")
+			So(features, ShouldContainKey, "fake")
+			So(features["fake"], ShouldResemble, map[string]interface{}{"fakefeature1": true, "fakefeature2": true, "fakefeature3": true})
+		})
+		Convey("the effective configuration is returned", func() {
+			config := get("/config")
+			So(config["sources"], ShouldResemble, map[string]interface{}{"kernel": map[string]interface{}{"configOpts": []interface{}{"DMI"}}})
+		})
+		Convey("errors of failed sources are returned", func() {
+			So(get("/errors"), ShouldResemble, map[string]interface{}{"panic_fake": "fake panic error"})
+		})
+		Convey("the result of the latest publish is returned", func() {
+			state.setPublishResult(Labels{"fake-fakefeature1": "true"}, fmt.Errorf("mock-error"))
+			publish := get("/publish")
+			So(publish["labels"], ShouldResemble, map[string]interface{}{"fake-fakefeature1": "true"})
+			So(publish["error"], ShouldEqual, "mock-error")
+		})
+	})
+}
+
+func TestExplain(t *testing.T) {
+	Convey("When explaining labeling decisions", t, func() {
+		explanations := explainSource(new(fake.Source), nil, regexp.MustCompile("fakefeature[12]"))
+		explanations = append(explanations, explainSource(new(panicfake.Source), nil, regexp.MustCompile(""))...)
+		explainOverrides(explanations)
+		explainMaster(explanations, []string{""}, regexp.MustCompile("fakefeature1"))
+
+		Convey("each candidate feature is traced to the stage that dropped it", func() {
+			So(explanations, ShouldResemble, []explanation{
+				{Source: "fake", Feature: "fakefeature1", Label: "fake-fakefeature1", Value: "true"},
+				{Source: "fake", Feature: "fakefeature2", Label: "fake-fakefeature2", Value: "true", Stage: stageMaster,
+					Reason: `"fake-fakefeature2" does not match the label whitelist "fakefeature1"`},
+				{Source: "fake", Feature: "fakefeature3", Label: "fake-fakefeature3", Value: "true", Stage: stageWorker,
+					Reason: `"fake-fakefeature3" does not match the label whitelist "fakefeature[12]"`},
+				{Source: "panic_fake", Feature: "*", Stage: stageSource, Reason: "discovery failed: fake panic error"},
+			})
+		})
+
+		Convey("features filtered out by the label filter of a source are reported", func() {
+			mockFeatureSource := new(source.MockFeatureSource)
+			mockFeatureSource.On("Name").Return("kernel")
+			mockFeatureSource.On("Discover").Return(source.Features{"config.NO_HZ": true, "version.major": "5"}, nil)
+			filters := newLabelFilters(sourcesConfig{"kernel": &kernel.Config{LabelFilterConfig: source.LabelFilterConfig{
+				LabelFilter: &labelfilter.Config{Exclude: []string{"^version"}},
+			}}})
+			explanations := explainSource(mockFeatureSource, filters["kernel"], regexp.MustCompile(""))
+			So(explanations, ShouldResemble, []explanation{
+				{Source: "kernel", Feature: "config.NO_HZ", Label: "kernel-config.NO_HZ", Value: "true"},
+				{Source: "kernel", Feature: "version.major", Stage: stageWorker,
+					Reason: `"version.major" matches the exclude pattern "^version" of the label filter`},
+			})
+		})
+
+		Convey("labels overridden by another source are reported", func() {
+			explanations := []explanation{
+				{Source: "local", Feature: "fake-fakefeature1", Label: "fake-fakefeature1", Value: "false"},
+				{Source: "fake", Feature: "fakefeature1", Label: "fake-fakefeature1", Value: "true"},
+			}
+			explainOverrides(explanations)
+			So(explanations[0].Stage, ShouldEqual, "")
+			So(explanations[1].Stage, ShouldEqual, stageWorker)
+			So(explanations[1].Reason, ShouldEqual, `overridden by source "local"`)
+		})
+
+		Convey("the decisions are written as a table", func() {
+			out := &bytes.Buffer{}
+			So(writeExplanations(out, explanations[:1]), ShouldBeNil)
+			So(out.String(), ShouldEqual,
+				"SOURCE  FEATURE       LABEL              VALUE  DECISION   REASON\n"+
+					"fake    fakefeature1  fake-fakefeature1  true   published  -\n")
+		})
+	})
+}
+
+func TestSnapshot(t *testing.T) {
+	Convey("When capturing a snapshot of a node", t, func() {
+		host, err := ioutil.TempDir("", "nfd-test-host-")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(host)
+		defer source.SetHostPaths(source.NewHostPaths(""))
+		defer source.SetCpuidReplay(nil, nil)
+
+		files := map[string]string{
+			"etc/os-release":                             "ID=fedora\nVERSION_ID=32\n",
+			"etc/passwd":                                 "root:x:0:0::/root:/bin/sh\n",
+			"proc/sys/kernel/osrelease":                  "5.8.0\n",
+			"sys/bus/pci/devices/0000:00:02.0/class":     "0x030000\n",
+			"sys/bus/pci/devices/0000:00:02.0/vendor":    "0x8086\n",
+			"sys/bus/pci/devices/0000:00:02.0/resource0": "binary",
+			"sys/class/iommu/dmar0/intel-iommu/version":  "1:0\n",
+			"usr/lib/modules/5.8.0/config":               "CONFIG_NO_HZ=y\n",
+			"proc/cmdline":                               "BOOT_IMAGE=/vmlinuz isolcpus=1-3\n",
+			"proc/modules":                               "kvm 1 0 - Live 0x0\n",
+			"proc/sys/vm/swappiness":                     "60\n",
+			"proc/sys/vm/overcommit_memory":              "0\n",
+		}
+		for name, content := range files {
+			p := filepath.Join(host, name)
+			So(os.MkdirAll(filepath.Dir(p), 0755), ShouldBeNil)
+			So(ioutil.WriteFile(p, []byte(content), 0644), ShouldBeNil)
+		}
+
+		// The custom rules and the sysctls of the kernel source make the
+		// sources read files depending on the configuration
+		options := `
+sources:
+  kernel:
+    cmdlineOpts: [isolcpus]
+    sysctlOpts:
+      - key: vm.swappiness
+  custom:
+    - name: kconfig
+      matchOn:
+        - kConfig: ["NO_HZ"]
+    - name: kmod
+      matchOn:
+        - loadedKMod: ["kvm"]
+    - name: sysctl
+      matchOn:
+        - sysctl: {"vm.overcommit_memory": "0"}
+`
+		sources := []string{"custom", "iommu", "kernel", "system"}
+		w, err := NewNfdWorker(Args{Sources: sources, HostPrefix: host + "/", Options: options})
+		So(err, ShouldBeNil)
+		worker := w.(*nfdWorker)
+
+		archive := &bytes.Buffer{}
+		So(worker.snapshot(archive), ShouldBeNil)
+
+		Convey("only the files read by the sources should be captured", func() {
+			dir, err := ioutil.TempDir("", "nfd-test-snapshot-")
+			So(err, ShouldBeNil)
+			defer os.RemoveAll(dir)
+
+			info, err := extractSnapshot(bytes.NewReader(archive.Bytes()), dir)
+			So(err, ShouldBeNil)
+			So(info.CpuidFlags, ShouldResemble, source.GetCpuidFlags())
+
+			captured := []string{}
+			err = filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+				if err == nil && !fi.IsDir() {
+					rel, _ := filepath.Rel(dir, p)
+					captured = append(captured, filepath.ToSlash(rel))
+				}
+				return err
+			})
+			So(err, ShouldBeNil)
+			So(captured, ShouldContain, "etc/os-release")
+			So(captured, ShouldContain, "proc/cmdline")
+			So(captured, ShouldContain, "proc/modules")
+			So(captured, ShouldContain, "proc/sys/kernel/osrelease")
+			So(captured, ShouldContain, "proc/sys/vm/overcommit_memory")
+			So(captured, ShouldContain, "proc/sys/vm/swappiness")
+			So(captured, ShouldContain, "usr/lib/modules/5.8.0/config")
+			So(captured, ShouldNotContain, "etc/passwd")
+			So(captured, ShouldNotContain, "sys/bus/pci/devices/0000:00:02.0/resource0")
+			fi, err := os.Stat(filepath.Join(dir, "sys/class/iommu/dmar0"))
+			So(err, ShouldBeNil)
+			So(fi.IsDir(), ShouldBeTrue)
+		})
+
+		Convey("replaying the snapshot should reproduce the labels of the node", func() {
+			liveSources, filters := worker.getSourcesAndFilters()
+			expected := discoverFeatures(liveSources, filters, worker.labelWhiteList, 0, nil).labels()
+
+			f, err := ioutil.TempFile("", "nfd-test-snapshot-")
+			So(err, ShouldBeNil)
+			defer os.Remove(f.Name())
+			_, err = f.Write(archive.Bytes())
+			f.Close()
+			So(err, ShouldBeNil)
+			So(os.RemoveAll(host), ShouldBeNil)
+
+			w, err := NewNfdWorker(Args{Sources: sources, Options: options})
+			So(err, ShouldBeNil)
+			replayer := w.(*nfdWorker)
+			dir, err := replayer.replaySnapshot(f.Name())
+			So(err, ShouldBeNil)
+			defer os.RemoveAll(dir)
+
+			replayer.configure("non-existing-file", options+`
+hostPaths:
+  etc: /etc
+`)
+			So(source.EtcDir, ShouldEqual, source.HostDir(dir+"/etc"))
+			replaySources, filters := replayer.getSourcesAndFilters()
+			labels := discoverFeatures(replaySources, filters, replayer.labelWhiteList, 0, nil).labels()
+			So(labels, ShouldResemble, expected)
+			So(labels["system"]["system-os_release.ID"], ShouldEqual, "fedora")
+			So(labels["iommu"]["iommu-enabled"], ShouldEqual, "true")
+			So(labels["kernel"]["kernel-config.NO_HZ"], ShouldEqual, "true")
+			So(labels["kernel"]["kernel-cmdline.isolated_cpus"], ShouldEqual, "1-3")
+			So(labels["kernel"]["kernel-sysctl.vm.swappiness"], ShouldEqual, "60")
+			So(labels["custom"], ShouldResemble, Labels{"custom-kconfig": "true", "custom-kmod": "true", "custom-sysctl": "true"})
+
+			// The local source is not part of the snapshot
+			selected := replayer.selectSources([]string{"kernel", "local"})
+			So(len(selected), ShouldEqual, 1)
+			So(selected[0].Name(), ShouldEqual, "kernel")
+		})
+
+		Convey("replayed CPU information should replace that of the running CPU", func() {
+			source.SetCpuidReplay([]string{"FAKEFLAG"}, nil)
+			So(source.GetCpuidFlags(), ShouldResemble, []string{"FAKEFLAG"})
+		})
+
+		Convey("archives with paths outside the extraction directory should be rejected", func() {
+			buf := &bytes.Buffer{}
+			gw := gzip.NewWriter(buf)
+			tw := tar.NewWriter(gw)
+			So(tw.WriteHeader(&tar.Header{Name: "../evil", Mode: 0644}), ShouldBeNil)
+			So(tw.Close(), ShouldBeNil)
+			So(gw.Close(), ShouldBeNil)
+
+			_, err := extractSnapshot(buf, host)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+// fakePlugin implements the FeatureSource service of feature source plugins
+type fakePlugin struct {
+	config string
+}
+
+func (p *fakePlugin) Name(context.Context, *plugin.NameRequest) (*plugin.NameReply, error) {
+	return &plugin.NameReply{Name: "fake_plugin"}, nil
+}
+
+func (p *fakePlugin) Discover(context.Context, *plugin.DiscoverRequest) (*plugin.DiscoverReply, error) {
+	return &plugin.DiscoverReply{Features: map[string]string{"feature": p.config}}, nil
+}
+
+func (p *fakePlugin) SetConfig(c context.Context, r *plugin.SetConfigRequest) (*plugin.SetConfigReply, error) {
+	p.config = ""
+	if len(r.Config) > 0 {
+		if err := json.Unmarshal(r.Config, &p.config); err != nil {
+			return nil, err
+		}
+	}
+	return &plugin.SetConfigReply{}, nil
+}
+
+func TestPluginRegistration(t *testing.T) {
+	Convey("When a feature source plugin registers", t, func() {
+		dir, err := ioutil.TempDir("", "nfd-test-")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+
+		// Serve the plugin
+		endpoint := filepath.Join(dir, "plugin.sock")
+		lis, err := net.Listen("unix", endpoint)
+		So(err, ShouldBeNil)
+		server := grpc.NewServer()
+		plugin.RegisterFeatureSourceServer(server, &fakePlugin{})
+		go server.Serve(lis)
+		defer server.Stop()
+
+		w, err := NewNfdWorker(Args{Sources: []string{"fake", "local"}})
+		So(err, ShouldBeNil)
+		worker := w.(*nfdWorker)
+		registration := &registrationServer{worker: worker}
+
+		_, err = registration.Register(context.Background(), &plugin.RegisterRequest{Endpoint: endpoint})
+		Convey("it should be enabled before the local source", func() {
+			So(err, ShouldBeNil)
+			sources := worker.getSources()
+			So(len(sources), ShouldEqual, 3)
+			So(sources[1].Name(), ShouldEqual, "fake_plugin")
+			So(sources[2].Name(), ShouldEqual, "local")
+		})
+		Convey("it should be configured and discover features", func() {
+			worker.configure("non-existing-file", `{"sources": {"fake_plugin": "configured"}}`)
+			labels := discoverSource(worker.getSource("fake_plugin"), nil, regexp.MustCompile("")).Labels
+			So(labels, ShouldResemble, Labels{"fake_plugin-feature": "configured"})
+		})
+		Convey("registering again should replace the plugin", func() {
+			_, err = registration.Register(context.Background(), &plugin.RegisterRequest{Endpoint: endpoint})
+			So(err, ShouldBeNil)
+			So(len(worker.getSources()), ShouldEqual, 3)
+		})
+		Convey("it should be removed once it becomes unavailable", func() {
+			So(err, ShouldBeNil)
+			server.Stop()
+			result := discoverSource(worker.getSource("fake_plugin"), nil, regexp.MustCompile(""))
+			So(result.Err, ShouldNotBeNil)
+			worker.removeUnavailablePlugins()
+			sources := worker.getSources()
+			So(len(sources), ShouldEqual, 2)
+			So(worker.getSource("fake_plugin"), ShouldBeNil)
+		})
+	})
+	Convey("When a plugin registers after the configuration is loaded", t, func() {
+		dir, err := ioutil.TempDir("", "nfd-test-")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+
+		endpoint := filepath.Join(dir, "plugin.sock")
+		lis, err := net.Listen("unix", endpoint)
+		So(err, ShouldBeNil)
+		server := grpc.NewServer()
+		plugin.RegisterFeatureSourceServer(server, &fakePlugin{})
+		go server.Serve(lis)
+		defer server.Stop()
+
+		w, err := NewNfdWorker(Args{Sources: []string{"fake"}})
+		So(err, ShouldBeNil)
+		worker := w.(*nfdWorker)
+		worker.configure("non-existing-file", `{"sources": {"fake_plugin": "preconfigured"}}`)
+
+		registration := &registrationServer{worker: worker}
+		_, err = registration.Register(context.Background(), &plugin.RegisterRequest{Endpoint: endpoint})
+		Convey("the configuration should be applied on registration", func() {
+			So(err, ShouldBeNil)
+			labels := discoverSource(worker.getSource("fake_plugin"), nil, regexp.MustCompile("")).Labels
+			So(labels, ShouldResemble, Labels{"fake_plugin-feature": "preconfigured"})
+		})
+	})
+	Convey("When a plugin with a non-existing endpoint registers", t, func() {
+		w, _ := NewNfdWorker(Args{})
+		registration := &registrationServer{worker: w.(*nfdWorker)}
+		_, err := registration.Register(context.Background(), &plugin.RegisterRequest{Endpoint: "/non-existing.sock"})
+		Convey("an error should be returned", func() {
+			So(err, ShouldNotBeNil)
+		})
 	})
 }
 
@@ -272,7 +966,7 @@ func TestAdvertiseFeatureLabels(t *testing.T) {
 
 		Convey("Correct labeling request is sent", func() {
 			mockClient.On("SetLabels", mock.AnythingOfType("*context.timerCtx"), mock.AnythingOfType("*labeler.SetLabelsRequest")).Return(&labeler.SetLabelsReply{}, nil)
-			err := advertiseFeatureLabels(mockClient, labels)
+			err := advertiseFeatureLabels(mockClient, labels, nil)
 			Convey("There should be no error", func() {
 				So(err, ShouldBeNil)
 			})
@@ -280,7 +974,29 @@ func TestAdvertiseFeatureLabels(t *testing.T) {
 		Convey("Labeling request fails", func() {
 			mockErr := errors.New("mock-error")
 			mockClient.On("SetLabels", mock.AnythingOfType("*context.timerCtx"), mock.AnythingOfType("*labeler.SetLabelsRequest")).Return(&labeler.SetLabelsReply{}, mockErr)
-			err := advertiseFeatureLabels(mockClient, labels)
+			err := advertiseFeatureLabels(mockClient, labels, nil)
+			Convey("An error should be returned", func() {
+				So(err, ShouldEqual, mockErr)
+			})
+		})
+	})
+}
+
+func TestWithdrawFeatureLabels(t *testing.T) {
+	Convey("When withdrawing labels", t, func() {
+		mockClient := &labeler.MockLabelerClient{}
+
+		Convey("Correct label removal request is sent", func() {
+			mockClient.On("RemoveLabels", mock.AnythingOfType("*context.timerCtx"), mock.AnythingOfType("*labeler.RemoveLabelsRequest")).Return(&labeler.RemoveLabelsReply{}, nil)
+			err := withdrawFeatureLabels(mockClient)
+			Convey("There should be no error", func() {
+				So(err, ShouldBeNil)
+			})
+		})
+		Convey("Label removal request fails", func() {
+			mockErr := errors.New("mock-error")
+			mockClient.On("RemoveLabels", mock.AnythingOfType("*context.timerCtx"), mock.AnythingOfType("*labeler.RemoveLabelsRequest")).Return(&labeler.RemoveLabelsReply{}, mockErr)
+			err := withdrawFeatureLabels(mockClient)
 			Convey("An error should be returned", func() {
 				So(err, ShouldEqual, mockErr)
 			})
@@ -22,10 +22,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/net/context"
@@ -33,6 +34,8 @@ import (
 	"google.golang.org/grpc/credentials"
 	"k8s.io/apimachinery/pkg/util/validation"
 	pb "sigs.k8s.io/node-feature-discovery/pkg/labeler"
+	"sigs.k8s.io/node-feature-discovery/pkg/labelfilter"
+	"sigs.k8s.io/node-feature-discovery/pkg/logger"
 	"sigs.k8s.io/node-feature-discovery/pkg/version"
 	"sigs.k8s.io/node-feature-discovery/source"
 	"sigs.k8s.io/node-feature-discovery/source/cpu"
@@ -45,21 +48,41 @@ import (
 	"sigs.k8s.io/node-feature-discovery/source/network"
 	"sigs.k8s.io/node-feature-discovery/source/panic_fake"
 	"sigs.k8s.io/node-feature-discovery/source/pci"
+	"sigs.k8s.io/node-feature-discovery/source/plugin"
 	"sigs.k8s.io/node-feature-discovery/source/storage"
 	"sigs.k8s.io/node-feature-discovery/source/system"
 	"sigs.k8s.io/node-feature-discovery/source/usb"
+	"sigs.k8s.io/node-feature-discovery/source/virt"
 	"sigs.k8s.io/yaml"
 )
 
 var (
-	stdoutLogger = log.New(os.Stdout, "", log.LstdFlags)
-	stderrLogger = log.New(os.Stderr, "", log.LstdFlags)
-	nodeName     = os.Getenv("NODE_NAME")
+	// nodeName is the name of the node nfd-worker is running on, resolved
+	// when the worker is created
+	nodeName string
+	log      = logger.WithValues("component", "nfd-worker")
 )
 
+// shutdownTimeout is the time to wait for discovery still running in the
+// background, and for connecting to nfd-master, on shutdown. Shutdown needs
+// to complete within the termination grace period of the pod.
+const shutdownTimeout = 5 * time.Second
+
 // Global config
 type NFDConfig struct {
-	Sources sourcesConfig
+	Core      coreConfig       `json:"core"`
+	HostPaths source.HostPaths `json:"hostPaths"`
+	Sources   sourcesConfig    `json:"sources"`
+}
+
+// coreConfig contains the configuration of nfd-worker itself
+type coreConfig struct {
+	// Sources are the names of the enabled built-in feature sources. Nil
+	// means that the --sources command line flag is used.
+	Sources []string `json:"sources,omitempty"`
+	// Plugins are the names of the feature source plugins that may be
+	// configured in the sources section before they have registered
+	Plugins []string `json:"plugins,omitempty"`
 }
 
 type sourcesConfig map[string]source.Config
@@ -67,33 +90,73 @@ type sourcesConfig map[string]source.Config
 // Labels are a Kubernetes representation of discovered features.
 type Labels map[string]string
 
+// SourceLabels are feature labels grouped by the name of the feature source
+// that produced them
+type SourceLabels map[string]Labels
+
 // Command line arguments
 type Args struct {
-	LabelWhiteList     string
-	CaFile             string
-	CertFile           string
-	KeyFile            string
-	ConfigFile         string
-	NoPublish          bool
-	Options            string
-	Oneshot            bool
-	Server             string
-	ServerNameOverride string
-	SleepInterval      time.Duration
-	Sources            []string
+	LabelWhiteList       string
+	LogFormat            string
+	CaFile               string
+	CertFile             string
+	KeyFile              string
+	MasterExtraLabelNs   []string
+	MasterLabelWhiteList string
+	ConfigFile           string
+	ExportFile           string
+	ExportFormat         string
+	ExportGroupBySource  bool
+	Explain              bool
+	ExplainFeature       string
+	HostPrefix           string
+	IntrospectionAddr    string
+	PluginSocket         string
+	NoPublish            bool
+	Options              string
+	Oneshot              bool
+	Replay               string
+	Server               string
+	ServerNameOverride   string
+	SleepInterval        time.Duration
+	Snapshot             bool
+	SnapshotFile         string
+	SourceTimeout        time.Duration
+	ValidateConfig       bool
+	Sources              []string
+	Verbosity            int
+	WithdrawLabels       bool
 }
 
 type NfdWorker interface {
 	Run() error
+	Stop()
 }
 
 type nfdWorker struct {
-	args           Args
-	clientConn     *grpc.ClientConn
-	client         pb.LabelerClient
-	config         NFDConfig
-	sources        []source.FeatureSource
+	args       Args
+	clientConn *grpc.ClientConn
+	client     pb.LabelerClient
+	config     NFDConfig
+	// builtins are all the built-in feature sources, enabled or not
+	builtins []source.FeatureSource
+	// sources are the enabled feature sources, including plugins
+	sources     []source.FeatureSource
+	sourcesLock sync.RWMutex
+	// configLock serializes (re-)configuration of the worker and its
+	// sources. Changing config or sources requires holding configLock, and
+	// sourcesLock for sources. Configuring the sources, which may be slow for
+	// plugins, is done without holding sourcesLock.
+	configLock sync.Mutex
+	// labelFilters are the compiled label filters of the sources, updated
+	// together with sources
+	labelFilters   labelFilters
 	labelWhiteList *regexp.Regexp
+	state          *workerState
+	// replayRoot is where the snapshot being replayed is extracted
+	replayRoot string
+	stop       chan struct{}
+	stopOnce   sync.Once
 }
 
 // Create new NfdWorker instance.
@@ -101,10 +164,20 @@ func NewNfdWorker(args Args) (NfdWorker, error) {
 	nfd := &nfdWorker{
 		args:    args,
 		sources: []source.FeatureSource{},
+		state:   newWorkerState(),
+		stop:    make(chan struct{}),
+	}
+
+	// Configure logging. The node name is added to the messages of all
+	// loggers, including the ones of the feature sources.
+	nodeName = os.Getenv("NODE_NAME")
+	logger.SetDefaultValues("node", nodeName)
+	if err := logger.Configure(args.Verbosity, args.LogFormat); err != nil {
+		return nfd, err
 	}
 
 	if args.SleepInterval > 0 && args.SleepInterval < time.Second {
-		stderrLogger.Printf("WARNING: too short sleep-intervall specified (%s), forcing to 1s", args.SleepInterval.String())
+		log.Warning("too short sleep-interval specified, forcing to 1s", "sleepInterval", args.SleepInterval)
 		args.SleepInterval = time.Second
 	}
 
@@ -121,8 +194,28 @@ func NewNfdWorker(args Args) (NfdWorker, error) {
 		}
 	}
 
+	// Check export related args
+	if err := validateExportFormat(args.ExportFormat); err != nil {
+		return nfd, err
+	}
+
 	// Figure out active sources
-	allSources := []source.FeatureSource{
+	nfd.builtins = builtinSources()
+	nfd.sources = nfd.selectSources(args.Sources)
+
+	// Compile labelWhiteList regex
+	var err error
+	nfd.labelWhiteList, err = regexp.Compile(args.LabelWhiteList)
+	if err != nil {
+		return nfd, fmt.Errorf("error parsing label whitelist regex (%s): %s", args.LabelWhiteList, err)
+	}
+
+	return nfd, nil
+}
+
+// builtinSources returns new instances of all the built-in feature sources
+func builtinSources() []source.FeatureSource {
+	return []source.FeatureSource{
 		&cpu.Source{},
 		&fake.Source{},
 		&iommu.Source{},
@@ -134,43 +227,70 @@ func NewNfdWorker(args Args) (NfdWorker, error) {
 		&storage.Source{},
 		&system.Source{},
 		&usb.Source{},
+		&virt.Source{},
 		&custom.Source{},
 		// local needs to be the last source so that it is able to override
 		// labels from other sources
 		&local.Source{},
 	}
+}
+
+// Run NfdWorker client. Returns if a fatal error is encountered, or, after
+// one request if OneShot is set to 'true' in the worker args.
+func (w *nfdWorker) Run() error {
+	log.Info("Node Feature Discovery Worker", "version", version.Get())
+
+	// Only validate the configuration
+	if w.args.ValidateConfig {
+		return w.validateConfig(os.Stdout)
+	}
 
-	sourceWhiteList := map[string]struct{}{}
-	for _, s := range args.Sources {
-		sourceWhiteList[strings.TrimSpace(s)] = struct{}{}
+	// Only capture a snapshot of the node
+	if w.args.Snapshot {
+		return w.writeSnapshot(w.args.SnapshotFile)
 	}
 
-	nfd.sources = []source.FeatureSource{}
-	for _, s := range allSources {
-		if _, enabled := sourceWhiteList[s.Name()]; enabled {
-			nfd.sources = append(nfd.sources, s)
+	// Inspect a snapshot of another node instead of the host
+	if w.args.Replay != "" {
+		dir, err := w.replaySnapshot(w.args.Replay)
+		if err != nil {
+			return fmt.Errorf("failed to replay snapshot: %v", err)
 		}
+		defer os.RemoveAll(dir)
+		// Labels of another node must never be published for this one
+		w.args.NoPublish = true
 	}
 
-	// Compile labelWhiteList regex
-	var err error
-	nfd.labelWhiteList, err = regexp.Compile(args.LabelWhiteList)
-	if err != nil {
-		return nfd, fmt.Errorf("error parsing label whitelist regex (%s): %s", args.LabelWhiteList, err)
+	// Explain the labeling decisions instead of labeling the node
+	if w.args.Explain {
+		return w.explain(os.Stdout)
 	}
 
-	return nfd, nil
-}
+	// Serve the introspection API
+	if w.args.IntrospectionAddr != "" {
+		err := w.startIntrospectionServer(w.args.IntrospectionAddr)
+		if err != nil {
+			return fmt.Errorf("failed to start introspection API: %v", err)
+		}
+	}
 
-// Run NfdWorker client. Returns if a fatal error is encountered, or, after
-// one request if OneShot is set to 'true' in the worker args.
-func (w *nfdWorker) Run() error {
-	stdoutLogger.Printf("Node Feature Discovery Worker %s", version.Get())
-	stdoutLogger.Printf("NodeName: '%s'", nodeName)
+	// Serve the plugin registration service. Plugins inspect the host they
+	// run on, so they are not used when replaying a snapshot.
+	if w.args.PluginSocket != "" && w.replayRoot == "" {
+		err := w.startPluginServer(w.args.PluginSocket)
+		if err != nil {
+			return fmt.Errorf("failed to start plugin registration: %v", err)
+		}
+	}
 
-	// Connect to NFD master
-	err := w.connect()
+	// Connect to NFD master, giving up if requested to stop
+	ctx, cancel := w.stopContext()
+	err := w.connect(ctx)
+	cancel()
 	if err != nil {
+		if w.stopped() {
+			return w.shutdown()
+		}
 		return fmt.Errorf("failed to connect: %v", err)
 	}
 	defer w.disconnect()
@@ -180,11 +300,28 @@ func (w *nfdWorker) Run() error {
 		w.configure(w.args.ConfigFile, w.args.Options)
 
 		// Get the set of feature labels.
-		labels := createFeatureLabels(w.sources, w.labelWhiteList)
+		sources, filters := w.getSourcesAndFilters()
+		results := discoverFeatures(sources, filters, w.labelWhiteList, w.args.SourceTimeout, w.stop)
+		if w.stopped() {
+			return w.shutdown()
+		}
+		w.state.setDiscoveryResults(results)
+		w.removeUnavailablePlugins()
+		sourceLabels := results.labels()
+		labels := sourceLabels.merge()
+
+		// Export the feature labels to a file or stdout. A failed export
+		// must not prevent advertising the labels.
+		if w.args.ExportFormat != "" {
+			if err := w.exportFeatureLabels(sourceLabels); err != nil {
+				log.Error(err, "failed to export labels")
+			}
+		}
 
 		// Update the node with the feature labels.
 		if w.client != nil {
-			err := advertiseFeatureLabels(w.client, labels)
+			err := advertiseFeatureLabels(w.client, labels, results.status())
+			w.state.setPublishResult(labels, err)
 			if err != nil {
 				return fmt.Errorf("failed to advertise labels: %s", err.Error())
 			}
@@ -194,19 +331,85 @@ func (w *nfdWorker) Run() error {
 			break
 		}
 
+		// Wait for the next round of discovery or a request to stop
+		var nextRound <-chan time.Time
 		if w.args.SleepInterval > 0 {
-			time.Sleep(w.args.SleepInterval)
+			nextRound = time.After(w.args.SleepInterval)
 		} else {
-			w.disconnect()
 			// Sleep forever
-			select {}
+			w.disconnect()
+		}
+		select {
+		case <-nextRound:
+		case <-w.stop:
+			return w.shutdown()
 		}
 	}
 	return nil
 }
 
-// connect creates a client connection to the NFD master
-func (w *nfdWorker) connect() error {
+// Stop makes a running NfdWorker exit. Connecting to nfd-master and feature
+// discovery are abandoned, the remaining sources are not discovered.
+func (w *nfdWorker) Stop() {
+	w.stopOnce.Do(func() { close(w.stop) })
+}
+
+// stopped returns true if the worker has been requested to stop
+func (w *nfdWorker) stopped() bool {
+	select {
+	case <-w.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// stopContext returns a context that is cancelled when the worker is
+// requested to stop
+func (w *nfdWorker) stopContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-w.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// shutdown waits for a while for the discovery still running in the
+// background to complete, and asks nfd-master to withdraw the labels of the
+// node, if requested
+func (w *nfdWorker) shutdown() error {
+	log.Info("shutting down")
+	if !running.wait(shutdownTimeout) {
+		log.Warning("feature discovery did not complete before shutdown", "timeout", shutdownTimeout)
+	}
+	if !w.args.WithdrawLabels || w.args.NoPublish {
+		return nil
+	}
+
+	// The connection is closed when sleeping forever, and may not have been
+	// established if stopped early
+	if w.client == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := w.connect(ctx); err != nil {
+			return fmt.Errorf("failed to connect: %v", err)
+		}
+	}
+	if err := withdrawFeatureLabels(w.client); err != nil {
+		return fmt.Errorf("failed to withdraw labels: %v", err)
+	}
+	w.state.setPublishResult(Labels{}, nil)
+	log.Info("labels withdrawn from the node")
+	return nil
+}
+
+// connect creates a client connection to the NFD master, giving up after a
+// timeout or when the context is cancelled
+func (w *nfdWorker) connect(ctx context.Context) error {
 	// Return a dummy connection in case of dry-run
 	if w.args.NoPublish {
 		return nil
@@ -218,7 +421,7 @@ func (w *nfdWorker) connect() error {
 	}
 
 	// Dial and create a client
-	dialCtx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	dialCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
 	defer cancel()
 	dialOpts := []grpc.DialOption{grpc.WithBlock()}
 	if w.args.CaFile != "" || w.args.CertFile != "" || w.args.KeyFile != "" {
@@ -265,10 +468,71 @@ func (w *nfdWorker) disconnect() {
 	w.client = nil
 }
 
+// getSources returns the currently enabled feature sources
+func (w *nfdWorker) getSources() []source.FeatureSource {
+	w.sourcesLock.RLock()
+	defer w.sourcesLock.RUnlock()
+	return w.sources
+}
+
+// getSourcesAndFilters returns the currently enabled feature sources and
+// their label filters
+func (w *nfdWorker) getSourcesAndFilters() ([]source.FeatureSource, labelFilters) {
+	w.sourcesLock.RLock()
+	defer w.sourcesLock.RUnlock()
+	return w.sources, w.labelFilters
+}
+
+// selectSources returns the built-in feature sources with the given names,
+// together with the currently registered plugins, in the order they are run.
+// The local source is never selected when replaying a snapshot.
+func (w *nfdWorker) selectSources(names []string) []source.FeatureSource {
+	enabled := map[string]struct{}{}
+	for _, name := range names {
+		enabled[strings.TrimSpace(name)] = struct{}{}
+	}
+
+	plugins := []source.FeatureSource{}
+	for _, s := range w.sources {
+		if _, ok := s.(*plugin.Source); ok {
+			plugins = append(plugins, s)
+		}
+	}
+
+	sources := []source.FeatureSource{}
+	for _, s := range w.builtins {
+		// local needs to be the last source so that it is able to
+		// override labels from other sources
+		if _, ok := s.(*local.Source); ok {
+			sources = append(sources, plugins...)
+			plugins = nil
+			// The feature files and hooks of the local source are those of
+			// nfd-worker itself, they are not part of a snapshot
+			if w.replayRoot != "" {
+				continue
+			}
+		}
+		if _, ok := enabled[s.Name()]; ok {
+			sources = append(sources, s)
+		}
+	}
+	return append(sources, plugins...)
+}
+
 // Parse configuration options
 func (w *nfdWorker) configure(filepath string, overrides string) {
-	// Create a new default config
-	c := NFDConfig{Sources: make(map[string]source.Config, len(w.sources))}
+	w.configLock.Lock()
+	defer w.configLock.Unlock()
+
+	// Create a new default config. Disabled sources are configured, too, so
+	// that they are ready to be enabled.
+	c := NFDConfig{
+		HostPaths: source.NewHostPaths(w.args.HostPrefix),
+		Sources:   make(map[string]source.Config, len(w.builtins)+len(w.sources)),
+	}
+	for _, s := range w.builtins {
+		c.Sources[s.Name()] = s.NewConfig()
+	}
 	for _, s := range w.sources {
 		c.Sources[s.Name()] = s.NewConfig()
 	}
@@ -276,133 +540,477 @@ func (w *nfdWorker) configure(filepath string, overrides string) {
 	// Try to read and parse config file
 	data, err := ioutil.ReadFile(filepath)
 	if err != nil {
-		stderrLogger.Printf("Failed to read config file: %s", err)
+		log.Error(err, "failed to read config file")
 	} else {
 		err = yaml.Unmarshal(data, &c)
 		if err != nil {
-			stderrLogger.Printf("Failed to parse config file: %s", err)
+			log.Error(err, "failed to parse config file", "path", filepath)
 		} else {
-			stdoutLogger.Printf("Configuration successfully loaded from %q", filepath)
+			for _, err := range validateConfigData(data, w.knownSources()) {
+				log.Warning("invalid configuration", "path", filepath, "reason", err)
+			}
+			log.Info("configuration successfully loaded", "path", filepath)
 		}
 	}
 
 	// Parse config overrides
 	err = yaml.Unmarshal([]byte(overrides), &c)
 	if err != nil {
-		stderrLogger.Printf("Failed to parse --options: %s", err)
+		log.Error(err, "failed to parse --options")
+	} else {
+		for _, err := range validateConfigData([]byte(overrides), w.knownSources()) {
+			log.Warning("invalid configuration", "path", "--options", "reason", err)
+		}
+	}
+
+	// The snapshot being replayed replaces all host directories
+	if w.replayRoot != "" {
+		c.HostPaths = source.NewHostPaths(w.replayRoot + "/")
 	}
 
+	// Enable and disable sources according to the config
+	if c.Core.Sources == nil {
+		c.Core.Sources = w.args.Sources
+	}
+	sources := w.selectSources(c.Core.Sources)
+	logSourceChanges(w.sources, sources)
+	filters := newLabelFilters(c.Sources)
+	w.sourcesLock.Lock()
+	w.sources = sources
+	w.labelFilters = filters
+	w.sourcesLock.Unlock()
+
 	w.config = c
+	w.state.setConfig(c)
+
+	// Set the layout of host directories inspected by the sources
+	running.setHostPaths(c.HostPaths)
 
 	// (Re-)configure all sources
-	for _, s := range w.sources {
-		s.SetConfig(c.Sources[s.Name()])
+	for _, s := range sources {
+		running.setConfig(s, c.Sources[s.Name()])
 	}
 }
 
-// createFeatureLabels returns the set of feature labels from the enabled
-// sources and the whitelist argument.
-func createFeatureLabels(sources []source.FeatureSource, labelWhiteList *regexp.Regexp) (labels Labels) {
-	labels = Labels{}
+// logSourceChanges logs the feature sources enabled and disabled when
+// replacing the old set of enabled sources with a new one
+func logSourceChanges(old, updated []source.FeatureSource) {
+	names := func(sources []source.FeatureSource) map[string]struct{} {
+		m := make(map[string]struct{}, len(sources))
+		for _, s := range sources {
+			m[s.Name()] = struct{}{}
+		}
+		return m
+	}
+	oldNames, newNames := names(old), names(updated)
+
+	for name := range newNames {
+		if _, ok := oldNames[name]; !ok {
+			log.Info("feature source enabled", "source", name)
+		}
+	}
+	for name := range oldNames {
+		if _, ok := newNames[name]; !ok {
+			log.Info("feature source disabled", "source", name)
+		}
+	}
+}
+
+// discoveryResult is the outcome of feature discovery of one source
+type discoveryResult struct {
+	// Features are the raw features returned by the source
+	Features source.Features
+	// Labels are the valid and whitelisted feature labels created from
+	// Features
+	Labels Labels
+	// Err is the error encountered during discovery, if any
+	Err error
+	// Status is the health status of the source, one of the SourceStatus*
+	// constants
+	Status string
+}
+
+// Health status of a feature source, reported to nfd-master
+const (
+	SourceStatusOK      = "ok"
+	SourceStatusError   = "error"
+	SourceStatusPanic   = "panic"
+	SourceStatusTimeout = "timeout"
+)
+
+// discoveryResults are the outcomes of feature discovery, keyed by the name of
+// the feature source
+type discoveryResults map[string]*discoveryResult
+
+// discoverFeatures runs feature discovery on all the enabled sources and
+// creates feature labels using the whitelist argument. Sources that do not
+// complete discovery within the timeout are marked as timed out. A
+// non-positive timeout disables the timeout. Discovery is abandoned when the
+// stop channel is closed, the results of the remaining sources are missing.
+func discoverFeatures(sources []source.FeatureSource, filters labelFilters, labelWhiteList *regexp.Regexp, timeout time.Duration, stop <-chan struct{}) discoveryResults {
+	results := discoveryResults{}
 
 	// Do feature discovery from all configured sources.
 	for _, source := range sources {
-		labelsFromSource, err := getFeatureLabels(source, labelWhiteList)
-		if err != nil {
-			stderrLogger.Printf("discovery failed for source [%s]: %s", source.Name(), err.Error())
-			stderrLogger.Printf("continuing ...")
-			continue
+		select {
+		case <-stop:
+			log.Info("discovery stopped, skipping the remaining sources")
+			return results
+		default:
+		}
+
+		result := discoverSourceWithTimeout(source, filters[source.Name()], labelWhiteList, timeout, stop)
+		if result.Err != nil {
+			log.Error(result.Err, "discovery failed, continuing with other sources", "source", source.Name())
 		}
 
-		for name, value := range labelsFromSource {
+		for name, value := range result.Labels {
 			// Log discovered feature.
-			stdoutLogger.Printf("%s = %s", name, value)
-			labels[name] = value
+			log.V(2).Info("feature discovered", "source", source.Name(), "feature", name, "value", value)
+		}
+		results[source.Name()] = result
+	}
+	return results
+}
+
+// labels returns the feature labels of all successfully discovered sources
+func (r discoveryResults) labels() SourceLabels {
+	labels := SourceLabels{}
+	for name, result := range r {
+		if result.Err == nil {
+			labels[name] = result.Labels
 		}
 	}
 	return labels
 }
 
-// getFeatureLabels returns node labels for features discovered by the
-// supplied source.
-func getFeatureLabels(source source.FeatureSource, labelWhiteList *regexp.Regexp) (labels Labels, err error) {
-	defer func() {
-		if r := recover(); r != nil {
-			stderrLogger.Printf("panic occurred during discovery of source [%s]: %v", source.Name(), r)
-			err = fmt.Errorf("%v", r)
+// status returns the health status of all sources
+func (r discoveryResults) status() map[string]*pb.SourceStatus {
+	status := make(map[string]*pb.SourceStatus, len(r))
+	for name, result := range r {
+		status[name] = &pb.SourceStatus{Status: result.Status}
+		if result.Err != nil {
+			status[name].Message = result.Err.Error()
+		}
+	}
+	return status
+}
+
+// discoverSourceWithTimeout runs discoverSource, giving up after the timeout
+// or when the stop channel is closed. A source that times out is left running
+// in the background and skipped until its discovery completes.
+func discoverSourceWithTimeout(source source.FeatureSource, filter *labelfilter.Filter, labelWhiteList *regexp.Regexp, timeout time.Duration, stop <-chan struct{}) *discoveryResult {
+	if timeout <= 0 {
+		return discoverSource(source, filter, labelWhiteList)
+	}
+
+	if !running.start(source) {
+		return &discoveryResult{
+			Err:    fmt.Errorf("previous discovery has not completed yet"),
+			Status: SourceStatusTimeout,
 		}
+	}
+
+	done := make(chan *discoveryResult, 1)
+	go func() {
+		defer running.done(source)
+		done <- discoverSource(source, filter, labelWhiteList)
 	}()
 
-	labels = Labels{}
-	features, err := source.Discover()
-	if err != nil {
-		return nil, err
+	select {
+	case result := <-done:
+		return result
+	case <-time.After(timeout):
+		return &discoveryResult{
+			Err:    fmt.Errorf("discovery did not complete within %v", timeout),
+			Status: SourceStatusTimeout,
+		}
+	case <-stop:
+		return &discoveryResult{
+			Err:    fmt.Errorf("discovery stopped"),
+			Status: SourceStatusTimeout,
+		}
 	}
+}
 
-	// Prefix for labels in the default namespace
-	prefix := source.Name() + "-"
-	switch source.(type) {
-	case *local.Source:
-		// Do not prefix labels from the hooks
-		prefix = ""
+// runningSources keeps track of the feature sources whose discovery is in
+// progress in the background. The configuration of a running source, and
+// the layout of host directories while any source is running, are not
+// changed until the discovery completes, so that a source always completes
+// with the state it was started with.
+type runningSources struct {
+	sync.Mutex
+	sources map[source.FeatureSource]struct{}
+	// pendingConfig is the configuration to apply to a source once it
+	// completes
+	pendingConfig map[source.FeatureSource]source.Config
+	// pendingHostPaths is the layout of host directories to apply once no
+	// source is running
+	pendingHostPaths *source.HostPaths
+	// idle is closed when no source is running any more
+	idle chan struct{}
+}
+
+var running = &runningSources{
+	sources:       map[source.FeatureSource]struct{}{},
+	pendingConfig: map[source.FeatureSource]source.Config{},
+}
+
+// start marks the source as running, returns false if it already is
+func (r *runningSources) start(s source.FeatureSource) bool {
+	r.Lock()
+	defer r.Unlock()
+	if _, ok := r.sources[s]; ok {
+		return false
+	}
+	if len(r.sources) == 0 {
+		r.idle = make(chan struct{})
 	}
+	r.sources[s] = struct{}{}
+	return true
+}
 
-	for k, v := range features {
-		// Split label name into namespace and name compoents. Use dummy 'ns'
-		// default namespace because there is no function to validate just
-		// the name part
-		split := strings.SplitN(k, "/", 2)
+// done marks the source as completed and applies the configuration changes
+// deferred while it was running
+func (r *runningSources) done(s source.FeatureSource) {
+	r.Lock()
+	defer r.Unlock()
+	// The source is kept marked as running while being configured, as
+	// configuring a plugin may take a while
+	for {
+		c, ok := r.pendingConfig[s]
+		if !ok {
+			break
+		}
+		delete(r.pendingConfig, s)
+		r.Unlock()
+		s.SetConfig(c)
+		r.Lock()
+	}
+	delete(r.sources, s)
+	if len(r.sources) == 0 {
+		if r.pendingHostPaths != nil {
+			source.SetHostPaths(*r.pendingHostPaths)
+			r.pendingHostPaths = nil
+		}
+		close(r.idle)
+	}
+}
+
+// wait waits until no source is running, returns false if sources are still
+// running after the timeout
+func (r *runningSources) wait(timeout time.Duration) bool {
+	r.Lock()
+	if len(r.sources) == 0 {
+		r.Unlock()
+		return true
+	}
+	idle := r.idle
+	r.Unlock()
+
+	select {
+	case <-idle:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
 
-		label := prefix + split[0]
-		nameForValidation := "ns/" + label
-		nameForWhiteListing := label
+// setConfig configures the source, or defers it if the source is running
+func (r *runningSources) setConfig(s source.FeatureSource, c source.Config) {
+	r.Lock()
+	if _, ok := r.sources[s]; ok {
+		r.pendingConfig[s] = c
+		r.Unlock()
+		log.Info("source is busy, deferring configuration until discovery completes", "source", s.Name())
+		return
+	}
+	delete(r.pendingConfig, s)
+	r.Unlock()
+
+	// Sources are only started by discovery which never runs concurrently
+	// with configuration, so the source cannot start before this
+	s.SetConfig(c)
+}
 
-		if len(split) == 2 {
-			label = k
-			nameForValidation = label
-			nameForWhiteListing = split[1]
+// setHostPaths changes the layout of host directories, or defers it if any
+// source is running
+func (r *runningSources) setHostPaths(p source.HostPaths) {
+	r.Lock()
+	defer r.Unlock()
+	if len(r.sources) > 0 {
+		if p != source.GetHostPaths() {
+			log.Info("sources are busy, deferring change of host directories until discovery completes")
 		}
+		r.pendingHostPaths = &p
+		return
+	}
+	r.pendingHostPaths = nil
+	source.SetHostPaths(p)
+}
 
-		// Validate label name.
-		errs := validation.IsQualifiedName(nameForValidation)
-		if len(errs) > 0 {
-			stderrLogger.Printf("Ignoring invalid feature name '%s': %s", label, errs)
-			continue
+// discoverSource runs feature discovery on the supplied source and creates
+// node labels for the discovered features.
+func discoverSource(source source.FeatureSource, filter *labelfilter.Filter, labelWhiteList *regexp.Regexp) (result *discoveryResult) {
+	result = &discoveryResult{Status: SourceStatusOK}
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error(fmt.Errorf("%v", r), "panic occurred during discovery", "source", source.Name())
+			result.Err = fmt.Errorf("%v", r)
+			result.Status = SourceStatusPanic
 		}
+	}()
+
+	result.Features, result.Err = source.Discover()
+	if result.Err != nil {
+		result.Status = SourceStatusError
+		return result
+	}
+	result.Labels = getFeatureLabels(source, result.Features, filter, labelWhiteList)
+
+	return result
+}
 
-		value := fmt.Sprintf("%v", v)
-		// Validate label value
-		errs = validation.IsValidLabelValue(value)
-		if len(errs) > 0 {
-			stderrLogger.Printf("Ignoring invalid feature value %s=%s: %s", label, value, errs)
+// getFeatureLabels returns node labels for features discovered by the
+// supplied source.
+func getFeatureLabels(source source.FeatureSource, features source.Features, filter *labelfilter.Filter, labelWhiteList *regexp.Regexp) Labels {
+	labels := Labels{}
+
+	prefix := labelPrefix(source)
+	for k, v := range features {
+		if err := filter.Check(k); err != nil {
+			log.V(1).Info("feature is filtered out by the label filter of the source and will not be published", "source", source.Name(), "feature", k, "reason", err)
 			continue
 		}
+		label, value, err := featureLabel(prefix, k, v, labelWhiteList)
+		switch err.(type) {
+		case nil:
+			labels[label] = value
+		case *whiteListError:
+			log.V(1).Info("feature does not match the label whitelist and will not be published", "source", source.Name(), "feature", label, "whitelist", labelWhiteList.String())
+		default:
+			log.Warning("ignoring invalid feature", "source", source.Name(), "feature", label, "value", value, "reason", err)
+		}
+	}
+	return labels
+}
 
-		// Skip if label doesn't match labelWhiteList
-		if !labelWhiteList.MatchString(nameForWhiteListing) {
-			stderrLogger.Printf("%q does not match the whitelist (%s) and will not be published.", nameForWhiteListing, labelWhiteList.String())
+// labelFilters are the compiled label filters of feature sources, keyed by
+// the name of the source
+type labelFilters map[string]*labelfilter.Filter
+
+// newLabelFilters compiles the label filters of the given source configs. An
+// invalid filter rejects all features of the source, so that a typo never
+// causes unwanted features to be published.
+func newLabelFilters(configs sourcesConfig) labelFilters {
+	filters := labelFilters{}
+	for name, c := range configs {
+		f, ok := c.(source.LabelFilterer)
+		if !ok || f.GetLabelFilter() == nil {
 			continue
 		}
+		filter, err := labelfilter.New(*f.GetLabelFilter())
+		if err != nil {
+			log.Error(err, "invalid label filter, no features of the source will be published", "source", name)
+			filter = labelfilter.RejectAll(err)
+		}
+		filters[name] = filter
+	}
+	return filters
+}
+
+// labelPrefix returns the prefix of labels in the default namespace created
+// from features of the supplied source
+func labelPrefix(source source.FeatureSource) string {
+	switch source.(type) {
+	case *local.Source:
+		// Do not prefix labels from the hooks
+		return ""
+	}
+	return source.Name() + "-"
+}
+
+// whiteListError is returned by featureLabel if the label does not match the
+// label whitelist
+type whiteListError struct {
+	name string
+	re   *regexp.Regexp
+}
+
+func (e *whiteListError) Error() string {
+	return fmt.Sprintf("%q does not match the label whitelist %q", e.name, e.re.String())
+}
+
+// featureLabel converts one feature into a label. An error describing the
+// reason is returned if the feature cannot be published.
+func featureLabel(prefix string, name string, v source.FeatureValue, labelWhiteList *regexp.Regexp) (string, string, error) {
+	// Split label name into namespace and name compoents. Use dummy 'ns'
+	// default namespace because there is no function to validate just
+	// the name part
+	split := strings.SplitN(name, "/", 2)
+
+	label := prefix + split[0]
+	nameForValidation := "ns/" + label
+	nameForWhiteListing := label
 
-		labels[label] = value
+	if len(split) == 2 {
+		label = name
+		nameForValidation = label
+		nameForWhiteListing = split[1]
 	}
-	return labels, nil
+	value := fmt.Sprintf("%v", v)
+
+	// Validate label name.
+	if errs := validation.IsQualifiedName(nameForValidation); len(errs) > 0 {
+		return label, value, fmt.Errorf("invalid name: %s", strings.Join(errs, "; "))
+	}
+
+	// Validate label value
+	if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+		return label, value, fmt.Errorf("invalid value: %s", strings.Join(errs, "; "))
+	}
+
+	// Skip if label doesn't match labelWhiteList
+	if !labelWhiteList.MatchString(nameForWhiteListing) {
+		return label, value, &whiteListError{name: nameForWhiteListing, re: labelWhiteList}
+	}
+
+	return label, value, nil
+}
+
+// withdrawFeatureLabels asks the NFD server to remove all the feature labels,
+// extended resources and annotations published for the node
+func withdrawFeatureLabels(client pb.LabelerClient) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	log.V(1).Info("sending label removal request to nfd-master")
+
+	req := pb.RemoveLabelsRequest{NfdVersion: version.Get(), NodeName: nodeName}
+	_, err := client.RemoveLabels(ctx, &req)
+	if err != nil {
+		log.Error(err, "failed to remove labels")
+		return err
+	}
+
+	return nil
 }
 
 // advertiseFeatureLabels advertises the feature labels to a Kubernetes node
-// via the NFD server.
-func advertiseFeatureLabels(client pb.LabelerClient, labels Labels) error {
+// via the NFD server, together with the health status of the feature sources.
+func advertiseFeatureLabels(client pb.LabelerClient, labels Labels, status map[string]*pb.SourceStatus) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	stdoutLogger.Printf("Sending labeling request to nfd-master")
+	log.V(1).Info("sending labeling request to nfd-master", "labels", len(labels))
 
 	labelReq := pb.SetLabelsRequest{Labels: labels,
-		NfdVersion: version.Get(),
-		NodeName:   nodeName}
+		SourceStatus: status,
+		NfdVersion:   version.Get(),
+		NodeName:     nodeName}
 	_, err := client.SetLabels(ctx, &labelReq)
 	if err != nil {
-		stderrLogger.Printf("failed to set node labels: %v", err)
+		log.Error(err, "failed to set node labels")
 		return err
 	}
 
@@ -420,15 +1028,46 @@ func (c *sourcesConfig) UnmarshalJSON(data []byte) error {
 
 	// Then parse each source-specific data structure
 	// NOTE: we expect 'c' to be pre-populated with correct per-source data
-	//       types. Non-pre-populated keys are ignored.
+	//       types. The data of non-pre-populated keys is stored as-is, as
+	//       it may be the config of a plugin that registers later.
 	for k, rawv := range raw {
 		if v, ok := (*c)[k]; ok {
 			err := yaml.Unmarshal(rawv, &v)
 			if err != nil {
 				return fmt.Errorf("failed to parse %q source config: %v", k, err)
 			}
+		} else {
+			(*c)[k] = &plugin.Config{RawMessage: rawv}
 		}
 	}
 
 	return nil
 }
+
+// merge combines the labels from all sources into one set of labels. Sources
+// are processed in alphabetical order, except for 'local' which always comes
+// last so that it is able to override labels from other sources.
+func (l SourceLabels) merge() Labels {
+	labels := Labels{}
+	for _, name := range l.sourceNames() {
+		for k, v := range l[name] {
+			labels[k] = v
+		}
+	}
+	return labels
+}
+
+// sourceNames returns the names of the sources in the order they are merged
+func (l SourceLabels) sourceNames() []string {
+	names := make([]string, 0, len(l))
+	for name := range l {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if names[i] == "local" || names[j] == "local" {
+			return names[j] == "local" && names[i] != "local"
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
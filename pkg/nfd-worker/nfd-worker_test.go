@@ -82,6 +82,12 @@ func TestNewNfdWorker(t *testing.T) {
 				So(err3, ShouldNotBeNil)
 			})
 		})
+		Convey("When an unsupported --export-format is specified", func() {
+			_, err := w.NewNfdWorker(w.Args{ExportFormat: "xml"})
+			Convey("An error should be returned", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
 	})
 }
 
@@ -96,6 +102,35 @@ func TestRun(t *testing.T) {
 				So(err, ShouldBeNil)
 			})
 		})
+		Convey("When stopping a worker that sleeps forever", func() {
+			worker, _ := w.NewNfdWorker(w.Args{SleepInterval: 0, Sources: []string{"fake"}, Server: "localhost:8192", WithdrawLabels: true})
+			errs := make(chan error)
+			go func() { errs <- worker.Run() }()
+			worker.Stop()
+			Convey("Run should return after withdrawing the labels", func() {
+				select {
+				case err := <-errs:
+					So(err, ShouldBeNil)
+				case <-time.After(10 * time.Second):
+					t.Fatal("timeout while waiting for nfd-worker to stop")
+				}
+			})
+		})
+		Convey("When stopping a worker that is connecting to nfd-master", func() {
+			worker, _ := w.NewNfdWorker(w.Args{Oneshot: true, Sources: []string{"fake"}, Server: "localhost:1"})
+			errs := make(chan error)
+			go func() { errs <- worker.Run() }()
+			time.Sleep(100 * time.Millisecond)
+			worker.Stop()
+			Convey("Run should return without waiting for the connection", func() {
+				select {
+				case err := <-errs:
+					So(err, ShouldBeNil)
+				case <-time.After(10 * time.Second):
+					t.Fatal("timeout while waiting for nfd-worker to stop")
+				}
+			})
+		})
 	})
 }
 
@@ -0,0 +1,157 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdworker
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	pb "sigs.k8s.io/node-feature-discovery/pkg/plugin"
+	"sigs.k8s.io/node-feature-discovery/source"
+	"sigs.k8s.io/node-feature-discovery/source/local"
+	"sigs.k8s.io/node-feature-discovery/source/plugin"
+)
+
+// startPluginServer starts serving the plugin Registration service on the
+// given Unix socket. The server runs in the background for the lifetime of
+// the process.
+func (w *nfdWorker) startPluginServer(socket string) error {
+	// Remove stale socket left behind by a previous instance
+	if err := os.Remove(socket); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	lis, err := net.Listen("unix", socket)
+	if err != nil {
+		return err
+	}
+
+	server := grpc.NewServer()
+	pb.RegisterRegistrationServer(server, &registrationServer{worker: w})
+	log.Info("plugin registration serving", "socket", socket)
+
+	go func() {
+		err := server.Serve(lis)
+		log.Error(err, "plugin registration stopped")
+	}()
+	return nil
+}
+
+// Implement RegistrationServer
+type registrationServer struct {
+	worker *nfdWorker
+}
+
+// Service Register
+func (s *registrationServer) Register(c context.Context, r *pb.RegisterRequest) (*pb.RegisterReply, error) {
+	p, err := plugin.Connect(r.Endpoint)
+	if err != nil {
+		log.Error(err, "plugin registration failed", "endpoint", r.Endpoint)
+		return &pb.RegisterReply{}, err
+	}
+
+	if err := s.worker.addPlugin(p); err != nil {
+		log.Error(err, "plugin registration failed", "endpoint", r.Endpoint)
+		p.Close()
+		return &pb.RegisterReply{}, err
+	}
+	log.Info("registered feature source plugin", "source", p.Name(), "endpoint", p.Endpoint())
+
+	return &pb.RegisterReply{}, nil
+}
+
+// addPlugin enables a feature source plugin, replacing any previously
+// registered plugin of the same name. Plugins cannot replace built-in sources.
+func (w *nfdWorker) addPlugin(p *plugin.Source) error {
+	w.configLock.Lock()
+	defer w.configLock.Unlock()
+
+	for _, s := range builtinSources() {
+		if s.Name() == p.Name() {
+			return fmt.Errorf("plugin name %q conflicts with a built-in feature source", p.Name())
+		}
+	}
+
+	// Apply the current configuration of the plugin, if any. The plugin is
+	// not yet visible to discovery so sourcesLock is not needed.
+	if c, ok := w.config.Sources[p.Name()].(*plugin.Config); ok {
+		p.SetConfig(c)
+	} else {
+		p.SetConfig(p.NewConfig())
+	}
+
+	added := false
+	var replaced *plugin.Source
+	sources := make([]source.FeatureSource, 0, len(w.sources)+1)
+	for _, s := range w.sources {
+		if old, ok := s.(*plugin.Source); ok && old.Name() == p.Name() {
+			replaced = old
+			continue
+		}
+		// local needs to be the last source so that it is able to
+		// override labels from other sources
+		if _, ok := s.(*local.Source); ok && !added {
+			sources = append(sources, p)
+			added = true
+		}
+		sources = append(sources, s)
+	}
+	if !added {
+		sources = append(sources, p)
+	}
+	w.sourcesLock.Lock()
+	w.sources = sources
+	w.sourcesLock.Unlock()
+
+	if replaced != nil {
+		replaced.Close()
+	}
+
+	return nil
+}
+
+// removeUnavailablePlugins disables the feature source plugins that could not
+// be reached on discovery, e.g. because the plugin has exited. A plugin that
+// is restarted needs to register again.
+func (w *nfdWorker) removeUnavailablePlugins() {
+	w.configLock.Lock()
+	defer w.configLock.Unlock()
+
+	removed := []*plugin.Source{}
+	sources := make([]source.FeatureSource, 0, len(w.sources))
+	for _, s := range w.sources {
+		if p, ok := s.(*plugin.Source); ok && p.Unavailable() {
+			removed = append(removed, p)
+			continue
+		}
+		sources = append(sources, s)
+	}
+	if len(removed) == 0 {
+		return
+	}
+	w.sourcesLock.Lock()
+	w.sources = sources
+	w.sourcesLock.Unlock()
+
+	for _, p := range removed {
+		log.Info("removed unavailable feature source plugin", "source", p.Name(), "endpoint", p.Endpoint())
+		p.Close()
+	}
+}
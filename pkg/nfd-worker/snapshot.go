@@ -0,0 +1,315 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdworker
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/cpuid"
+	"sigs.k8s.io/node-feature-discovery/pkg/version"
+	"sigs.k8s.io/node-feature-discovery/source"
+)
+
+// snapshotInfoFile is the name of the file in a snapshot archive holding the
+// information not read from the host directories
+const snapshotInfoFile = "snapshot.json"
+
+// snapshotInfo describes a snapshot and holds the CPU information of the
+// captured node
+type snapshotInfo struct {
+	NodeName   string         `json:"nodeName"`
+	Version    string         `json:"version"`
+	CpuidFlags []string       `json:"cpuidFlags"`
+	Cpuid      []cpuid.Record `json:"cpuid"`
+}
+
+// snapshotDir is a host directory captured in a snapshot
+type snapshotDir struct {
+	// Name is the path of the directory in the snapshot archive
+	Name string
+	Dir  source.HostDir
+}
+
+// snapshotDirs returns the host directories captured in a snapshot. The
+// archive layout mirrors the host root so that an extracted snapshot can be
+// used as a host prefix.
+func snapshotDirs(p source.HostPaths) []snapshotDir {
+	return []snapshotDir{
+		{Name: "boot", Dir: p.Boot},
+		{Name: "dev", Dir: p.Dev},
+		{Name: "etc", Dir: p.Etc},
+		{Name: "lib", Dir: p.Lib},
+		{Name: "proc", Dir: p.Proc},
+		{Name: "run", Dir: p.Run},
+		{Name: "sys", Dir: p.Sys},
+		{Name: "usr/lib", Dir: p.UsrLib},
+		{Name: "usr/src", Dir: p.UsrSrc},
+	}
+}
+
+// snapshotName returns the path in the snapshot archive of a host path, or
+// false if the path is not located in any of the captured host directories
+func snapshotName(dirs []snapshotDir, p string) (string, bool) {
+	name, matched := "", ""
+	for _, d := range dirs {
+		rel, err := filepath.Rel(string(d.Dir), p)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+			continue
+		}
+		// Prefer the most specific directory, host directories may be
+		// nested when configured individually
+		if len(d.Dir) > len(matched) {
+			name, matched = path.Join(d.Name, filepath.ToSlash(rel)), string(d.Dir)
+		}
+	}
+	return name, matched != ""
+}
+
+// writeSnapshot captures a snapshot of the node into a file, or to stdout
+func (w *nfdWorker) writeSnapshot(filename string) error {
+	if filename == "" || filename == exportStdout {
+		return w.snapshot(os.Stdout)
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %v", err)
+	}
+	if err := w.snapshot(f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write snapshot file: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot file: %v", err)
+	}
+	log.Info("snapshot written", "path", filename)
+	return nil
+}
+
+// snapshot runs feature discovery once and writes the files read by the
+// feature sources, together with the CPU information, into a gzipped tar
+// archive
+func (w *nfdWorker) snapshot(out io.Writer) error {
+	w.configure(w.args.ConfigFile, w.args.Options)
+
+	// Run discovery so that all host files accessed, and all CPUID queries
+	// made, by the sources with the effective configuration get recorded
+	source.StartRecording()
+	sources, filters := w.getSourcesAndFilters()
+	discoverFeatures(sources, filters, w.labelWhiteList, w.args.SourceTimeout, nil)
+	accesses := source.StopRecording()
+
+	info := snapshotInfo{
+		NodeName:   nodeName,
+		Version:    version.Get(),
+		CpuidFlags: source.GetCpuidFlags(),
+		Cpuid:      cpuid.Records(),
+	}
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	gw := gzip.NewWriter(out)
+	tw := tar.NewWriter(gw)
+
+	err = tw.WriteHeader(&tar.Header{Name: snapshotInfoFile, Mode: 0644, Size: int64(len(data))})
+	if err == nil {
+		_, err = tw.Write(data)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %v", snapshotInfoFile, err)
+	}
+
+	if err := writeSnapshotFiles(tw, snapshotDirs(source.GetHostPaths()), accesses); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// writeSnapshotFiles writes the host files accessed by the feature sources
+// into a tar archive. Files whose content was not read are captured as empty
+// files.
+func writeSnapshotFiles(tw *tar.Writer, snapshotDirs []snapshotDir, accesses []source.HostAccess) error {
+	dirs := map[string]struct{}{}
+	files := map[string][]byte{}
+	for _, a := range accesses {
+		name, ok := snapshotName(snapshotDirs, a.Path)
+		if !ok {
+			log.V(1).Info("skipping file outside host directories in snapshot", "path", a.Path)
+			continue
+		}
+
+		// Follow symlinks, sysfs is full of them
+		fi, err := os.Stat(a.Path)
+		if err != nil {
+			log.V(1).Info("skipping file in snapshot", "path", a.Path, "reason", err)
+			continue
+		}
+		if fi.IsDir() {
+			dirs[name] = struct{}{}
+		} else if !fi.Mode().IsRegular() || !a.Content {
+			// Only the existence of device nodes, and of files not read
+			// by the sources, matters. They are captured as empty files.
+			files[name] = nil
+		} else {
+			// The size reported for sysfs and procfs files is not
+			// reliable so read the whole file before writing the header
+			data, err := ioutil.ReadFile(a.Path)
+			if err != nil {
+				log.V(1).Info("skipping file in snapshot", "path", a.Path, "reason", err)
+				continue
+			}
+			files[name] = data
+		}
+		for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+			dirs[dir] = struct{}{}
+		}
+	}
+
+	// Write directories first so that they precede their content
+	dirNames := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		dirNames = append(dirNames, dir)
+	}
+	sort.Strings(dirNames)
+	for _, dir := range dirNames {
+		if err := tw.WriteHeader(&tar.Header{Name: dir + "/", Typeflag: tar.TypeDir, Mode: 0755}); err != nil {
+			return fmt.Errorf("failed to write %s: %v", dir, err)
+		}
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(files[name]))}); err != nil {
+			return fmt.Errorf("failed to write %s: %v", name, err)
+		}
+		if _, err := tw.Write(files[name]); err != nil {
+			return fmt.Errorf("failed to write %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// replaySnapshot extracts a snapshot archive into a temporary directory and
+// makes the feature sources inspect it instead of the host. The caller is
+// responsible for removing the returned directory.
+func (w *nfdWorker) replaySnapshot(filename string) (string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	dir, err := ioutil.TempDir("", "nfd-snapshot-")
+	if err != nil {
+		return "", err
+	}
+	info, err := extractSnapshot(f, dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	log.Info("replaying snapshot", "path", filename, "snapshotNode", info.NodeName, "snapshotVersion", info.Version)
+
+	// Non-nil values so that the CPU of this node is never consulted
+	if info.CpuidFlags == nil {
+		info.CpuidFlags = []string{}
+	}
+	if info.Cpuid == nil {
+		info.Cpuid = []cpuid.Record{}
+	}
+	source.SetCpuidReplay(info.CpuidFlags, info.Cpuid)
+
+	w.replayRoot = dir
+	return dir, nil
+}
+
+// extractSnapshot extracts a snapshot archive into the given directory
+func extractSnapshot(r io.Reader, dir string) (snapshotInfo, error) {
+	info := snapshotInfo{}
+	foundInfo := false
+
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return info, fmt.Errorf("invalid snapshot archive: %v", err)
+	}
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return info, fmt.Errorf("invalid snapshot archive: %v", err)
+		}
+
+		name := path.Clean(hdr.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return info, fmt.Errorf("invalid path %q in snapshot archive", hdr.Name)
+		}
+		target := filepath.Join(dir, filepath.FromSlash(name))
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return info, err
+			}
+		case tar.TypeReg:
+			data, err := ioutil.ReadAll(tr)
+			if err != nil {
+				return info, fmt.Errorf("failed to read %q from snapshot archive: %v", hdr.Name, err)
+			}
+			if name == snapshotInfoFile {
+				if err := json.Unmarshal(data, &info); err != nil {
+					return info, fmt.Errorf("failed to parse %s: %v", snapshotInfoFile, err)
+				}
+				foundInfo = true
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return info, err
+			}
+			if err := ioutil.WriteFile(target, data, 0644); err != nil {
+				return info, err
+			}
+		default:
+			return info, fmt.Errorf("unsupported entry %q in snapshot archive", hdr.Name)
+		}
+	}
+
+	if !foundInfo {
+		return info, fmt.Errorf("%s missing from snapshot archive", snapshotInfoFile)
+	}
+	return info, nil
+}
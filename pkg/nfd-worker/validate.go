@@ -0,0 +1,285 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfdworker
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/node-feature-discovery/source"
+	"sigs.k8s.io/yaml"
+)
+
+// validateConfigData strictly parses configuration data, in JSON or YAML
+// format, and checks it for errors. Unknown fields in source configs are
+// reported, as well as semantic errors found by source configs implementing
+// the ConfigValidator interface. The configuration of plugins listed in
+// core.plugins that have not registered is not checked, other unknown feature
+// sources are reported.
+func validateConfigData(data []byte, sources []source.FeatureSource) []error {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return []error{err}
+	}
+
+	raw := struct {
+		Core      json.RawMessage            `json:"core"`
+		HostPaths json.RawMessage            `json:"hostPaths"`
+		Sources   map[string]json.RawMessage `json:"sources"`
+	}{}
+	if err := strictUnmarshal(jsonData, &raw); err != nil {
+		return []error{err}
+	}
+
+	errs := []error{}
+	core := coreConfig{}
+	if raw.Core != nil {
+		if err := strictUnmarshal(raw.Core, &core); err != nil {
+			return []error{fmt.Errorf("failed to parse core config: %v", err)}
+		}
+		for _, err := range checkFieldNames(raw.Core, reflect.TypeOf(core), "") {
+			errs = append(errs, fmt.Errorf("failed to parse core config: %v", err))
+		}
+		for _, name := range core.Sources {
+			if findSource(sources, strings.TrimSpace(name)) == nil {
+				errs = append(errs, fmt.Errorf("unknown feature source %q in core.sources", name))
+			}
+		}
+		for _, name := range core.Plugins {
+			if findSource(builtinSources(), name) != nil {
+				errs = append(errs, fmt.Errorf("plugin name %q in core.plugins conflicts with a built-in feature source", name))
+			}
+		}
+	}
+
+	if raw.HostPaths != nil {
+		hostPaths := source.NewHostPaths("")
+		if err := strictUnmarshal(raw.HostPaths, &hostPaths); err != nil {
+			return []error{fmt.Errorf("failed to parse hostPaths: %v", err)}
+		}
+		for _, err := range checkFieldNames(raw.HostPaths, reflect.TypeOf(hostPaths), "") {
+			errs = append(errs, fmt.Errorf("failed to parse hostPaths: %v", err))
+		}
+		for _, err := range hostPaths.Validate() {
+			errs = append(errs, fmt.Errorf("invalid hostPaths: %v", err))
+		}
+	}
+
+	names := make([]string, 0, len(raw.Sources))
+	for name := range raw.Sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		s := findSource(sources, name)
+		if s == nil {
+			if !isDeclaredPlugin(core, name) {
+				errs = append(errs, fmt.Errorf("unknown feature source %q in sources, plugins need to be listed in core.plugins", name))
+			}
+			continue
+		}
+
+		c := s.NewConfig()
+		if c == nil {
+			continue
+		}
+		if err := strictUnmarshal(raw.Sources[name], c); err != nil {
+			errs = append(errs, fmt.Errorf("failed to parse %q source config: %v", name, err))
+			continue
+		}
+		for _, err := range checkFieldNames(raw.Sources[name], reflect.TypeOf(c), "") {
+			errs = append(errs, fmt.Errorf("failed to parse %q source config: %v", name, err))
+		}
+		if f, ok := c.(source.LabelFilterer); ok && f.GetLabelFilter() != nil {
+			for _, err := range f.GetLabelFilter().Validate() {
+				errs = append(errs, fmt.Errorf("invalid %q source config: labelFilter: %v", name, err))
+			}
+		}
+		if v, ok := c.(source.ConfigValidator); ok {
+			for _, err := range v.Validate() {
+				errs = append(errs, fmt.Errorf("invalid %q source config: %v", name, err))
+			}
+		}
+	}
+	return errs
+}
+
+// unregisteredPlugins returns the names of the plugins listed in core.plugins
+// and configured in the configuration data that are not found in sources,
+// i.e. plugins that have not registered
+func unregisteredPlugins(data []byte, sources []source.FeatureSource) []string {
+	raw := struct {
+		Core    coreConfig                 `json:"core"`
+		Sources map[string]json.RawMessage `json:"sources"`
+	}{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+
+	names := []string{}
+	for name := range raw.Sources {
+		if findSource(sources, name) == nil && isDeclaredPlugin(raw.Core, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isDeclaredPlugin returns true if the name is listed in core.plugins
+func isDeclaredPlugin(core coreConfig, name string) bool {
+	for _, p := range core.Plugins {
+		if strings.TrimSpace(p) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// findSource returns the feature source with the given name, or nil
+func findSource(sources []source.FeatureSource, name string) source.FeatureSource {
+	for _, s := range sources {
+		if s.Name() == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// strictUnmarshal decodes JSON data, failing on unknown fields
+func strictUnmarshal(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// checkFieldNames reports object keys in JSON data that do not exactly match
+// a field of the given type. It complements strictUnmarshal, as
+// encoding/json matches field names case-insensitively.
+func checkFieldNames(data []byte, t reflect.Type, path string) []error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	// Types doing their own decoding are not inspected
+	if reflect.PtrTo(t).Implements(reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()) {
+		return nil
+	}
+
+	errs := []error{}
+	switch t.Kind() {
+	case reflect.Struct:
+		obj := map[string]json.RawMessage{}
+		if json.Unmarshal(data, &obj) != nil {
+			return nil
+		}
+		fields := jsonFields(t)
+		for key, value := range obj {
+			field, ok := fields[key]
+			if !ok {
+				errs = append(errs, fmt.Errorf("unknown field %q", path+key))
+				continue
+			}
+			errs = append(errs, checkFieldNames(value, field.Type, path+key+".")...)
+		}
+	case reflect.Slice, reflect.Array:
+		items := []json.RawMessage{}
+		if json.Unmarshal(data, &items) != nil {
+			return nil
+		}
+		for i, item := range items {
+			errs = append(errs, checkFieldNames(item, t.Elem(), fmt.Sprintf("%s[%d].", strings.TrimSuffix(path, "."), i))...)
+		}
+	case reflect.Map:
+		obj := map[string]json.RawMessage{}
+		if json.Unmarshal(data, &obj) != nil {
+			return nil
+		}
+		for key, value := range obj {
+			errs = append(errs, checkFieldNames(value, t.Elem(), path+key+".")...)
+		}
+	}
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return errs
+}
+
+// jsonFields returns the fields of a struct type keyed by their JSON name
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := map[string]reflect.StructField{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" {
+			for k, v := range jsonFields(f.Type) {
+				fields[k] = v
+			}
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f
+	}
+	return fields
+}
+
+// knownSources returns all feature sources whose configuration is accepted,
+// i.e. all built-in sources and the currently registered plugins
+func (w *nfdWorker) knownSources() []source.FeatureSource {
+	return append(builtinSources(), w.sources...)
+}
+
+// validateConfig validates the config file and the --options, writing the
+// errors found to out
+func (w *nfdWorker) validateConfig(out io.Writer) error {
+	data, err := ioutil.ReadFile(w.args.ConfigFile)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	numErrs := 0
+	for _, input := range []struct {
+		name string
+		data []byte
+	}{
+		{w.args.ConfigFile, data},
+		{"--options", []byte(w.args.Options)},
+	} {
+		for _, err := range validateConfigData(input.data, w.knownSources()) {
+			fmt.Fprintf(out, "%s: %v\n", input.name, err)
+			numErrs++
+		}
+		for _, name := range unregisteredPlugins(input.data, w.knownSources()) {
+			fmt.Fprintf(out, "%s: note: not checking the config of plugin %q, it has not registered\n", input.name, name)
+		}
+	}
+
+	if numErrs > 0 {
+		return fmt.Errorf("configuration is invalid, %d error(s) found", numErrs)
+	}
+	fmt.Fprintf(out, "%s: configuration is valid\n", w.args.ConfigFile)
+	return nil
+}
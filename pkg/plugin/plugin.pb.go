@@ -0,0 +1,570 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: plugin.proto
+
+package plugin
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type RegisterRequest struct {
+	// Path of the Unix socket where the plugin serves the FeatureSource service
+	Endpoint             string   `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RegisterRequest) Reset()         { *m = RegisterRequest{} }
+func (m *RegisterRequest) String() string { return proto.CompactTextString(m) }
+func (*RegisterRequest) ProtoMessage()    {}
+func (*RegisterRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{0}
+}
+
+func (m *RegisterRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegisterRequest.Unmarshal(m, b)
+}
+func (m *RegisterRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegisterRequest.Marshal(b, m, deterministic)
+}
+func (m *RegisterRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegisterRequest.Merge(m, src)
+}
+func (m *RegisterRequest) XXX_Size() int {
+	return xxx_messageInfo_RegisterRequest.Size(m)
+}
+func (m *RegisterRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegisterRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegisterRequest proto.InternalMessageInfo
+
+func (m *RegisterRequest) GetEndpoint() string {
+	if m != nil {
+		return m.Endpoint
+	}
+	return ""
+}
+
+type RegisterReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RegisterReply) Reset()         { *m = RegisterReply{} }
+func (m *RegisterReply) String() string { return proto.CompactTextString(m) }
+func (*RegisterReply) ProtoMessage()    {}
+func (*RegisterReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{1}
+}
+
+func (m *RegisterReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RegisterReply.Unmarshal(m, b)
+}
+func (m *RegisterReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RegisterReply.Marshal(b, m, deterministic)
+}
+func (m *RegisterReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RegisterReply.Merge(m, src)
+}
+func (m *RegisterReply) XXX_Size() int {
+	return xxx_messageInfo_RegisterReply.Size(m)
+}
+func (m *RegisterReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_RegisterReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RegisterReply proto.InternalMessageInfo
+
+type NameRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NameRequest) Reset()         { *m = NameRequest{} }
+func (m *NameRequest) String() string { return proto.CompactTextString(m) }
+func (*NameRequest) ProtoMessage()    {}
+func (*NameRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{2}
+}
+
+func (m *NameRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NameRequest.Unmarshal(m, b)
+}
+func (m *NameRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NameRequest.Marshal(b, m, deterministic)
+}
+func (m *NameRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NameRequest.Merge(m, src)
+}
+func (m *NameRequest) XXX_Size() int {
+	return xxx_messageInfo_NameRequest.Size(m)
+}
+func (m *NameRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_NameRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NameRequest proto.InternalMessageInfo
+
+type NameReply struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NameReply) Reset()         { *m = NameReply{} }
+func (m *NameReply) String() string { return proto.CompactTextString(m) }
+func (*NameReply) ProtoMessage()    {}
+func (*NameReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{3}
+}
+
+func (m *NameReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NameReply.Unmarshal(m, b)
+}
+func (m *NameReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NameReply.Marshal(b, m, deterministic)
+}
+func (m *NameReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NameReply.Merge(m, src)
+}
+func (m *NameReply) XXX_Size() int {
+	return xxx_messageInfo_NameReply.Size(m)
+}
+func (m *NameReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_NameReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NameReply proto.InternalMessageInfo
+
+func (m *NameReply) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type DiscoverRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DiscoverRequest) Reset()         { *m = DiscoverRequest{} }
+func (m *DiscoverRequest) String() string { return proto.CompactTextString(m) }
+func (*DiscoverRequest) ProtoMessage()    {}
+func (*DiscoverRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{4}
+}
+
+func (m *DiscoverRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiscoverRequest.Unmarshal(m, b)
+}
+func (m *DiscoverRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiscoverRequest.Marshal(b, m, deterministic)
+}
+func (m *DiscoverRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiscoverRequest.Merge(m, src)
+}
+func (m *DiscoverRequest) XXX_Size() int {
+	return xxx_messageInfo_DiscoverRequest.Size(m)
+}
+func (m *DiscoverRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiscoverRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiscoverRequest proto.InternalMessageInfo
+
+type DiscoverReply struct {
+	Features             map[string]string `protobuf:"bytes,1,rep,name=features,proto3" json:"features,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *DiscoverReply) Reset()         { *m = DiscoverReply{} }
+func (m *DiscoverReply) String() string { return proto.CompactTextString(m) }
+func (*DiscoverReply) ProtoMessage()    {}
+func (*DiscoverReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{5}
+}
+
+func (m *DiscoverReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiscoverReply.Unmarshal(m, b)
+}
+func (m *DiscoverReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiscoverReply.Marshal(b, m, deterministic)
+}
+func (m *DiscoverReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiscoverReply.Merge(m, src)
+}
+func (m *DiscoverReply) XXX_Size() int {
+	return xxx_messageInfo_DiscoverReply.Size(m)
+}
+func (m *DiscoverReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiscoverReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiscoverReply proto.InternalMessageInfo
+
+func (m *DiscoverReply) GetFeatures() map[string]string {
+	if m != nil {
+		return m.Features
+	}
+	return nil
+}
+
+type SetConfigRequest struct {
+	// Configuration of the source from the nfd-worker config file, in JSON
+	Config               []byte   `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetConfigRequest) Reset()         { *m = SetConfigRequest{} }
+func (m *SetConfigRequest) String() string { return proto.CompactTextString(m) }
+func (*SetConfigRequest) ProtoMessage()    {}
+func (*SetConfigRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{6}
+}
+
+func (m *SetConfigRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetConfigRequest.Unmarshal(m, b)
+}
+func (m *SetConfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetConfigRequest.Marshal(b, m, deterministic)
+}
+func (m *SetConfigRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetConfigRequest.Merge(m, src)
+}
+func (m *SetConfigRequest) XXX_Size() int {
+	return xxx_messageInfo_SetConfigRequest.Size(m)
+}
+func (m *SetConfigRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetConfigRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetConfigRequest proto.InternalMessageInfo
+
+func (m *SetConfigRequest) GetConfig() []byte {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+type SetConfigReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetConfigReply) Reset()         { *m = SetConfigReply{} }
+func (m *SetConfigReply) String() string { return proto.CompactTextString(m) }
+func (*SetConfigReply) ProtoMessage()    {}
+func (*SetConfigReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_22a625af4bc1cc87, []int{7}
+}
+
+func (m *SetConfigReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetConfigReply.Unmarshal(m, b)
+}
+func (m *SetConfigReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetConfigReply.Marshal(b, m, deterministic)
+}
+func (m *SetConfigReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetConfigReply.Merge(m, src)
+}
+func (m *SetConfigReply) XXX_Size() int {
+	return xxx_messageInfo_SetConfigReply.Size(m)
+}
+func (m *SetConfigReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetConfigReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetConfigReply proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*RegisterRequest)(nil), "plugin.RegisterRequest")
+	proto.RegisterType((*RegisterReply)(nil), "plugin.RegisterReply")
+	proto.RegisterType((*NameRequest)(nil), "plugin.NameRequest")
+	proto.RegisterType((*NameReply)(nil), "plugin.NameReply")
+	proto.RegisterType((*DiscoverRequest)(nil), "plugin.DiscoverRequest")
+	proto.RegisterType((*DiscoverReply)(nil), "plugin.DiscoverReply")
+	proto.RegisterMapType((map[string]string)(nil), "plugin.DiscoverReply.FeaturesEntry")
+	proto.RegisterType((*SetConfigRequest)(nil), "plugin.SetConfigRequest")
+	proto.RegisterType((*SetConfigReply)(nil), "plugin.SetConfigReply")
+}
+
+func init() { proto.RegisterFile("plugin.proto", fileDescriptor_22a625af4bc1cc87) }
+
+var fileDescriptor_22a625af4bc1cc87 = []byte{
+	// 336 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0xcf, 0x4e, 0xc2, 0x40,
+	0x10, 0xc6, 0x2d, 0x20, 0x81, 0x81, 0x0a, 0xac, 0x82, 0x4d, 0x2f, 0x92, 0xf5, 0x42, 0x4c, 0x24,
+	0x06, 0x2f, 0x46, 0x4d, 0x38, 0xf8, 0xe7, 0x64, 0x3c, 0x94, 0x27, 0xa8, 0x38, 0x90, 0xc6, 0xb2,
+	0xbb, 0x6e, 0xb7, 0x24, 0x7d, 0x08, 0xdf, 0xc9, 0x47, 0x33, 0xdd, 0xee, 0x96, 0x7f, 0xde, 0xf6,
+	0x9b, 0xf9, 0xbe, 0x61, 0xe6, 0x47, 0xa1, 0x2d, 0xe2, 0x74, 0x19, 0xb1, 0xb1, 0x90, 0x5c, 0x71,
+	0x52, 0x2f, 0x14, 0xbd, 0x86, 0x4e, 0x80, 0xcb, 0x28, 0x51, 0x28, 0x03, 0xfc, 0x4e, 0x31, 0x51,
+	0xc4, 0x87, 0x06, 0xb2, 0x4f, 0xc1, 0x23, 0xa6, 0x3c, 0x67, 0xe8, 0x8c, 0x9a, 0x41, 0xa9, 0x69,
+	0x07, 0xdc, 0x8d, 0x5d, 0xc4, 0x19, 0x75, 0xa1, 0xf5, 0x1e, 0xae, 0xd0, 0x64, 0xe9, 0x05, 0x34,
+	0x0b, 0x29, 0xe2, 0x8c, 0x10, 0xa8, 0xb1, 0x70, 0x85, 0x66, 0x88, 0x7e, 0xd3, 0x1e, 0x74, 0x9e,
+	0xa3, 0x64, 0xce, 0xd7, 0xe5, 0xef, 0xd1, 0x1f, 0x07, 0xdc, 0x4d, 0x2d, 0x0f, 0x4e, 0xa1, 0xb1,
+	0xc0, 0x50, 0xa5, 0x12, 0x13, 0xcf, 0x19, 0x56, 0x47, 0xad, 0xc9, 0xe5, 0xd8, 0x6c, 0xbf, 0x63,
+	0x1c, 0xbf, 0x1a, 0xd7, 0x0b, 0x53, 0x32, 0x0b, 0xca, 0x90, 0xff, 0x00, 0xee, 0x4e, 0x8b, 0x74,
+	0xa1, 0xfa, 0x85, 0x99, 0xd9, 0x24, 0x7f, 0x92, 0x33, 0x38, 0x5e, 0x87, 0x71, 0x8a, 0x5e, 0x45,
+	0xd7, 0x0a, 0x71, 0x5f, 0xb9, 0x73, 0xe8, 0x15, 0x74, 0x67, 0xa8, 0x9e, 0x38, 0x5b, 0x44, 0x4b,
+	0xcb, 0x64, 0x00, 0xf5, 0xb9, 0x2e, 0xe8, 0x11, 0xed, 0xc0, 0x28, 0xda, 0x85, 0x93, 0x2d, 0xaf,
+	0x88, 0xb3, 0xc9, 0x1b, 0xb4, 0x0b, 0x42, 0x32, 0x54, 0x11, 0x67, 0xe4, 0x11, 0x1a, 0x96, 0x18,
+	0x39, 0xb7, 0x57, 0xec, 0x21, 0xf7, 0xfb, 0x87, 0x8d, 0x1c, 0xee, 0xd1, 0xe4, 0xd7, 0x29, 0x2f,
+	0x99, 0xf1, 0x54, 0xce, 0x91, 0xdc, 0x40, 0x2d, 0x27, 0x4c, 0x4e, 0x6d, 0x64, 0x0b, 0xbf, 0xdf,
+	0xdb, 0x2d, 0xea, 0x19, 0xf9, 0x06, 0x96, 0xda, 0x66, 0x83, 0xbd, 0x3f, 0xc1, 0xef, 0x1f, 0x36,
+	0x8a, 0xf4, 0x14, 0x9a, 0xe5, 0x85, 0xc4, 0xb3, 0xae, 0x7d, 0x40, 0xfe, 0xe0, 0x9f, 0x8e, 0x1e,
+	0xf0, 0x51, 0xd7, 0x1f, 0xdc, 0xed, 0xdf, 0x00, 0x7f, 0xbc, 0x5c, 0xeb, 0x80, 0x02, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// RegistrationClient is the client API for Registration service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type RegistrationClient interface {
+	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterReply, error)
+}
+
+type registrationClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewRegistrationClient(cc *grpc.ClientConn) RegistrationClient {
+	return &registrationClient{cc}
+}
+
+func (c *registrationClient) Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterReply, error) {
+	out := new(RegisterReply)
+	err := c.cc.Invoke(ctx, "/plugin.Registration/Register", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RegistrationServer is the server API for Registration service.
+type RegistrationServer interface {
+	Register(context.Context, *RegisterRequest) (*RegisterReply, error)
+}
+
+// UnimplementedRegistrationServer can be embedded to have forward compatible implementations.
+type UnimplementedRegistrationServer struct {
+}
+
+func (*UnimplementedRegistrationServer) Register(ctx context.Context, req *RegisterRequest) (*RegisterReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
+}
+
+func RegisterRegistrationServer(s *grpc.Server, srv RegistrationServer) {
+	s.RegisterService(&_Registration_serviceDesc, srv)
+}
+
+func _Registration_Register_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistrationServer).Register(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/plugin.Registration/Register",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistrationServer).Register(ctx, req.(*RegisterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Registration_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "plugin.Registration",
+	HandlerType: (*RegistrationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Register",
+			Handler:    _Registration_Register_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "plugin.proto",
+}
+
+// FeatureSourceClient is the client API for FeatureSource service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type FeatureSourceClient interface {
+	Name(ctx context.Context, in *NameRequest, opts ...grpc.CallOption) (*NameReply, error)
+	Discover(ctx context.Context, in *DiscoverRequest, opts ...grpc.CallOption) (*DiscoverReply, error)
+	SetConfig(ctx context.Context, in *SetConfigRequest, opts ...grpc.CallOption) (*SetConfigReply, error)
+}
+
+type featureSourceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewFeatureSourceClient(cc *grpc.ClientConn) FeatureSourceClient {
+	return &featureSourceClient{cc}
+}
+
+func (c *featureSourceClient) Name(ctx context.Context, in *NameRequest, opts ...grpc.CallOption) (*NameReply, error) {
+	out := new(NameReply)
+	err := c.cc.Invoke(ctx, "/plugin.FeatureSource/Name", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *featureSourceClient) Discover(ctx context.Context, in *DiscoverRequest, opts ...grpc.CallOption) (*DiscoverReply, error) {
+	out := new(DiscoverReply)
+	err := c.cc.Invoke(ctx, "/plugin.FeatureSource/Discover", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *featureSourceClient) SetConfig(ctx context.Context, in *SetConfigRequest, opts ...grpc.CallOption) (*SetConfigReply, error) {
+	out := new(SetConfigReply)
+	err := c.cc.Invoke(ctx, "/plugin.FeatureSource/SetConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FeatureSourceServer is the server API for FeatureSource service.
+type FeatureSourceServer interface {
+	Name(context.Context, *NameRequest) (*NameReply, error)
+	Discover(context.Context, *DiscoverRequest) (*DiscoverReply, error)
+	SetConfig(context.Context, *SetConfigRequest) (*SetConfigReply, error)
+}
+
+// UnimplementedFeatureSourceServer can be embedded to have forward compatible implementations.
+type UnimplementedFeatureSourceServer struct {
+}
+
+func (*UnimplementedFeatureSourceServer) Name(ctx context.Context, req *NameRequest) (*NameReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Name not implemented")
+}
+func (*UnimplementedFeatureSourceServer) Discover(ctx context.Context, req *DiscoverRequest) (*DiscoverReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Discover not implemented")
+}
+func (*UnimplementedFeatureSourceServer) SetConfig(ctx context.Context, req *SetConfigRequest) (*SetConfigReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetConfig not implemented")
+}
+
+func RegisterFeatureSourceServer(s *grpc.Server, srv FeatureSourceServer) {
+	s.RegisterService(&_FeatureSource_serviceDesc, srv)
+}
+
+func _FeatureSource_Name_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FeatureSourceServer).Name(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/plugin.FeatureSource/Name",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FeatureSourceServer).Name(ctx, req.(*NameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FeatureSource_Discover_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiscoverRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FeatureSourceServer).Discover(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/plugin.FeatureSource/Discover",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FeatureSourceServer).Discover(ctx, req.(*DiscoverRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FeatureSource_SetConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FeatureSourceServer).SetConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/plugin.FeatureSource/SetConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FeatureSourceServer).SetConfig(ctx, req.(*SetConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _FeatureSource_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "plugin.FeatureSource",
+	HandlerType: (*FeatureSourceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Name",
+			Handler:    _FeatureSource_Name_Handler,
+		},
+		{
+			MethodName: "Discover",
+			Handler:    _FeatureSource_Discover_Handler,
+		},
+		{
+			MethodName: "SetConfig",
+			Handler:    _FeatureSource_SetConfig_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "plugin.proto",
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package source
 
 import (
+	"fmt"
 	"path/filepath"
 )
 
@@ -24,10 +25,22 @@ var (
 	pathPrefix = "/"
 	// BootPath is where the /boot directory of the system to be inspected is located
 	BootDir = HostDir(pathPrefix + "boot")
+	// DevDir is where the /dev directory of the system to be inspected is located
+	DevDir = HostDir(pathPrefix + "dev")
 	// EtcPath is where the /etc directory of the system to be inspected is located
 	EtcDir = HostDir(pathPrefix + "etc")
+	// LibDir is where the /lib directory of the system to be inspected is located
+	LibDir = HostDir(pathPrefix + "lib")
+	// ProcfsDir is where the /proc directory of the system to be inspected is located
+	ProcfsDir = HostDir(pathPrefix + "proc")
+	// RunDir is where the /run directory of the system to be inspected is located
+	RunDir = HostDir(pathPrefix + "run")
 	// SysfsPath is where the /sys directory of the system to be inspected is located
 	SysfsDir = HostDir(pathPrefix + "sys")
+	// UsrLibDir is where the /usr/lib directory of the system to be inspected is located
+	UsrLibDir = HostDir(pathPrefix + "usr/lib")
+	// UsrSrcDir is where the /usr/src directory of the system to be inspected is located
+	UsrSrcDir = HostDir(pathPrefix + "usr/src")
 )
 
 // HostDir is a helper for handling host system directories
@@ -37,3 +50,90 @@ type HostDir string
 func (d HostDir) Path(elem ...string) string {
 	return filepath.Join(append([]string{string(d)}, elem...)...)
 }
+
+// HostPaths is the layout of the host system directories inspected by the
+// feature sources
+type HostPaths struct {
+	Boot   HostDir `json:"boot,omitempty"`
+	Dev    HostDir `json:"dev,omitempty"`
+	Etc    HostDir `json:"etc,omitempty"`
+	Lib    HostDir `json:"lib,omitempty"`
+	Proc   HostDir `json:"proc,omitempty"`
+	Run    HostDir `json:"run,omitempty"`
+	Sys    HostDir `json:"sys,omitempty"`
+	UsrLib HostDir `json:"usrLib,omitempty"`
+	UsrSrc HostDir `json:"usrSrc,omitempty"`
+}
+
+// NewHostPaths returns a layout where all host directories are located under
+// the given prefix, e.g. prefix "/host-" means that /sys is located at
+// /host-sys. An empty prefix means the default prefix set at build time.
+func NewHostPaths(prefix string) HostPaths {
+	if prefix == "" {
+		prefix = pathPrefix
+	}
+	return HostPaths{
+		Boot:   HostDir(prefix + "boot"),
+		Dev:    HostDir(prefix + "dev"),
+		Etc:    HostDir(prefix + "etc"),
+		Lib:    HostDir(prefix + "lib"),
+		Proc:   HostDir(prefix + "proc"),
+		Run:    HostDir(prefix + "run"),
+		Sys:    HostDir(prefix + "sys"),
+		UsrLib: HostDir(prefix + "usr/lib"),
+		UsrSrc: HostDir(prefix + "usr/src"),
+	}
+}
+
+// GetHostPaths returns the layout of host directories currently in use
+func GetHostPaths() HostPaths {
+	return HostPaths{
+		Boot:   BootDir,
+		Dev:    DevDir,
+		Etc:    EtcDir,
+		Lib:    LibDir,
+		Proc:   ProcfsDir,
+		Run:    RunDir,
+		Sys:    SysfsDir,
+		UsrLib: UsrLibDir,
+		UsrSrc: UsrSrcDir,
+	}
+}
+
+// SetHostPaths changes the layout of host directories used by all feature
+// sources
+func SetHostPaths(p HostPaths) {
+	BootDir = p.Boot
+	DevDir = p.Dev
+	EtcDir = p.Etc
+	LibDir = p.Lib
+	ProcfsDir = p.Proc
+	RunDir = p.Run
+	SysfsDir = p.Sys
+	UsrLibDir = p.UsrLib
+	UsrSrcDir = p.UsrSrc
+}
+
+// Validate checks that all host directories are absolute paths
+func (p HostPaths) Validate() []error {
+	errs := []error{}
+	for _, d := range []struct {
+		name string
+		dir  HostDir
+	}{
+		{"boot", p.Boot},
+		{"dev", p.Dev},
+		{"etc", p.Etc},
+		{"lib", p.Lib},
+		{"proc", p.Proc},
+		{"run", p.Run},
+		{"sys", p.Sys},
+		{"usrLib", p.UsrLib},
+		{"usrSrc", p.UsrSrc},
+	} {
+		if !filepath.IsAbs(string(d.dir)) {
+			errs = append(errs, fmt.Errorf("%s: %q is not an absolute path", d.name, d.dir))
+		}
+	}
+	return errs
+}
@@ -17,27 +17,42 @@ limitations under the License.
 package cpu
 
 import (
-	"io/ioutil"
-	"log"
+	"fmt"
+	"regexp"
 
+	"sigs.k8s.io/node-feature-discovery/pkg/labelfilter"
+	"sigs.k8s.io/node-feature-discovery/pkg/logger"
 	"sigs.k8s.io/node-feature-discovery/source"
 	"sigs.k8s.io/node-feature-discovery/source/internal/cpuidutils"
 )
 
+var log = logger.WithValues("source", "cpu")
+
 // Configuration file options
-type cpuidConfig struct {
+
+// attributeConfig selects the attributes of a feature, e.g. the cpuid flags,
+// to publish
+type attributeConfig struct {
 	AttributeBlacklist []string `json:"attributeBlacklist,omitempty"`
 	AttributeWhitelist []string `json:"attributeWhitelist,omitempty"`
 }
 
+type modelConfig struct {
+	LabelFields []string `json:"labelFields,omitempty"`
+}
+
 type Config struct {
-	Cpuid cpuidConfig `json:"cpuid,omitempty"`
+	Cpuid         attributeConfig `json:"cpuid,omitempty"`
+	Model         modelConfig     `json:"model,omitempty"`
+	Vulnerability attributeConfig `json:"vulnerability,omitempty"`
+
+	source.LabelFilterConfig
 }
 
 // newDefaultConfig returns a new config with pre-populated defaults
 func newDefaultConfig() *Config {
 	return &Config{
-		cpuidConfig{
+		Cpuid: attributeConfig{
 			AttributeBlacklist: []string{
 				"BMI1",
 				"BMI2",
@@ -66,19 +81,32 @@ func newDefaultConfig() *Config {
 			},
 			AttributeWhitelist: []string{},
 		},
+		Model: modelConfig{
+			LabelFields: []string{"vendor_id", "family", "id", "stepping"},
+		},
+		Vulnerability: attributeConfig{
+			AttributeBlacklist: []string{},
+			AttributeWhitelist: []string{},
+		},
 	}
 }
 
-// Filter for cpuid labels
-type keyFilter struct {
-	keys      map[string]struct{}
-	whitelist bool
+// Validate method of the ConfigValidator interface
+func (c *Config) Validate() []error {
+	errs := []error{}
+	for _, field := range c.Model.LabelFields {
+		if !isValidModelLabelField(field) {
+			errs = append(errs, fmt.Errorf("invalid field %q in model.labelFields, must be one of %v", field, cpuModelFields))
+		}
+	}
+	return errs
 }
 
 // Implement FeatureSource interface
 type Source struct {
-	config      *Config
-	cpuidFilter *keyFilter
+	config              *Config
+	cpuidFilter         *labelfilter.Filter
+	vulnerabilityFilter *labelfilter.Filter
 }
 
 func (s Source) Name() string { return "cpu" }
@@ -94,108 +122,211 @@ func (s *Source) SetConfig(conf source.Config) {
 	switch v := conf.(type) {
 	case *Config:
 		s.config = v
-		s.initCpuidFilter()
+		s.cpuidFilter = newAttributeFilter(v.Cpuid)
+		s.vulnerabilityFilter = newAttributeFilter(v.Vulnerability)
 	default:
-		log.Printf("PANIC: invalid config type: %T", conf)
+		log.Error(fmt.Errorf("invalid config type: %T", conf), "failed to configure source")
 	}
 }
 
 func (s *Source) Discover() (source.Features, error) {
 	features := source.Features{}
 
-	// Check if hyper-threading seems to be enabled
-	found, err := haveThreadSiblings()
+	// Detect CPU topology
+	topology, err := discoverTopology()
 	if err != nil {
-		log.Printf("ERROR: failed to detect hyper-threading: %v", err)
-	} else if found {
-		features["hardware_multithreading"] = true
+		log.Error(err, "failed to detect CPU topology")
+
+		// Fall back to checking if hyper-threading seems to be enabled
+		found, err := haveThreadSiblings()
+		if err != nil {
+			log.Error(err, "failed to detect hyper-threading")
+		} else if found {
+			features["hardware_multithreading"] = true
+		}
+	} else {
+		features["topology.sockets"] = topology.Sockets
+		features["topology.cores"] = topology.Cores
+		features["topology.cores_per_socket"] = topology.CoresPerSocket
+		features["topology.threads_per_core"] = topology.ThreadsPerCore
+		if topology.OfflineCPUs {
+			features["topology.cpus_offline"] = true
+		}
+		// Hyper-threading is enabled if any core has multiple threads
+		if topology.ThreadsPerCore > 1 {
+			features["hardware_multithreading"] = true
+		}
 	}
 
 	// Check SST-BF
-	found, err = discoverSSTBF()
+	found, err := discoverSSTBF()
 	if err != nil {
-		log.Printf("ERROR: failed to detect SST-BF: %v", err)
+		log.Error(err, "failed to detect SST-BF")
 	} else if found {
 		features["power.sst_bf.enabled"] = true
 	}
 
+	// Check SST-CP and SST-TF
+	sst, err := discoverSST()
+	if err != nil {
+		log.Error(err, "failed to detect SST-CP and SST-TF")
+	} else {
+		for k, v := range sst {
+			if v {
+				features["power."+k] = true
+			}
+		}
+	}
+
 	// Detect CPUID
 	cpuidFlags := cpuidutils.GetCpuidFlags()
 	for _, f := range cpuidFlags {
-		if s.cpuidFilter.unmask(f) {
+		if s.cpuidFilter.Check(f) == nil {
 			features["cpuid."+f] = true
 		}
 	}
 
+	// Detect ISA level from the full set of flags, ignoring the filter
+	for k, v := range discoverISA(cpuidFlags) {
+		features["isa."+k] = v
+	}
+
+	// Detect CPU model
+	model, err := getCPUModel()
+	if err != nil {
+		log.Error(err, "failed to detect CPU model")
+	} else {
+		for k, v := range modelFeatures(model, s.config.Model.LabelFields) {
+			features["model."+k] = v
+		}
+	}
+
 	// Detect pstate features
 	pstate, err := detectPstate()
 	if err != nil {
-		log.Printf("ERROR: %v", err)
+		log.Error(err, "failed to detect pstate")
 	} else {
 		for k, v := range pstate {
 			features["pstate."+k] = v
 		}
 	}
 
+	// Detect cpufreq features
+	cpufreq, err := detectCpufreq()
+	if err != nil {
+		log.Error(err, "failed to detect cpufreq")
+	} else {
+		for k, v := range cpufreq {
+			features["cpufreq."+k] = v
+		}
+	}
+
+	// Detect cpuidle features
+	cpuidle, err := detectCpuidle()
+	if err != nil {
+		log.Error(err, "failed to detect cpuidle")
+	} else {
+		for k, v := range cpuidle {
+			features["cpuidle."+k] = v
+		}
+	}
+
+	// Detect CPU vulnerabilities
+	vulnerabilities, err := discoverVulnerabilities()
+	if err != nil {
+		log.Error(err, "failed to detect CPU vulnerabilities")
+	} else {
+		for name, v := range vulnerabilities {
+			if s.vulnerabilityFilter.Check(name) != nil {
+				continue
+			}
+			features["vulnerability."+name] = v.Status
+			if v.SMTVulnerable {
+				features["vulnerability."+name+".smt_vulnerable"] = true
+			}
+		}
+	}
+
+	// Detect confidential computing technologies
+	for k, v := range discoverSecurity() {
+		features["security."+k] = v
+	}
+
 	// Detect RDT features
 	rdt := discoverRDT()
 	for _, f := range rdt {
 		features["rdt."+f] = true
 	}
+	resctrl, err := discoverResctrl()
+	if err != nil {
+		log.Error(err, "failed to detect RDT resources")
+	} else {
+		for k, v := range resctrl {
+			features["rdt."+k] = v
+		}
+	}
 
 	return features, nil
 }
 
-// Check if any (online) CPUs have thread siblings
-func haveThreadSiblings() (bool, error) {
+// DroppedFeatures method of the FeatureExplainer interface
+func (s *Source) DroppedFeatures() ([]source.DroppedFeature, error) {
+	dropped := droppedAttributes("cpuid", cpuidutils.GetCpuidFlags(), s.config.Cpuid, s.cpuidFilter)
 
-	files, err := ioutil.ReadDir(source.SysfsDir.Path("bus/cpu/devices"))
+	vulnerabilities, err := discoverVulnerabilities()
 	if err != nil {
-		return false, err
+		return dropped, err
 	}
+	names := make([]string, 0, len(vulnerabilities))
+	for name := range vulnerabilities {
+		names = append(names, name)
+	}
+	dropped = append(dropped, droppedAttributes("vulnerability", names, s.config.Vulnerability, s.vulnerabilityFilter)...)
 
-	for _, file := range files {
-		// Try to read siblings from topology
-		siblings, err := ioutil.ReadFile(source.SysfsDir.Path("bus/cpu/devices", file.Name(), "topology/thread_siblings_list"))
-		if err != nil {
-			return false, err
-		}
-		for _, char := range siblings {
-			// If list separator found, we determine that there are multiple siblings
-			if char == ',' || char == '-' {
-				return true, nil
-			}
+	model, err := getCPUModel()
+	if err != nil {
+		return dropped, err
+	}
+	published := modelFeatures(model, s.config.Model.LabelFields)
+	for _, f := range cpuModelFields {
+		if _, ok := published[f]; !ok && model[f] != "" {
+			dropped = append(dropped, source.DroppedFeature{Name: "model." + f, Reason: "not listed in model.labelFields"})
 		}
 	}
-	// No siblings were found
-	return false, nil
+	return dropped, nil
 }
 
-func (s *Source) initCpuidFilter() {
-	newFilter := keyFilter{keys: map[string]struct{}{}}
-	if len(s.config.Cpuid.AttributeWhitelist) > 0 {
-		for _, k := range s.config.Cpuid.AttributeWhitelist {
-			newFilter.keys[k] = struct{}{}
+// newAttributeFilter creates a filter of attributes from the attribute
+// whitelist, or from the blacklist if the whitelist is empty
+func newAttributeFilter(a attributeConfig) *labelfilter.Filter {
+	c := labelfilter.Config{}
+	if len(a.AttributeWhitelist) > 0 {
+		for _, k := range a.AttributeWhitelist {
+			c.Include = append(c.Include, "^"+regexp.QuoteMeta(k)+"$")
 		}
-		newFilter.whitelist = true
 	} else {
-		for _, k := range s.config.Cpuid.AttributeBlacklist {
-			newFilter.keys[k] = struct{}{}
+		for _, k := range a.AttributeBlacklist {
+			c.Exclude = append(c.Exclude, "^"+regexp.QuoteMeta(k)+"$")
 		}
-		newFilter.whitelist = false
 	}
-	s.cpuidFilter = &newFilter
+	// Quoted patterns always compile
+	f, _ := labelfilter.New(c)
+	return f
 }
 
-func (f keyFilter) unmask(k string) bool {
-	if f.whitelist {
-		if _, ok := f.keys[k]; ok {
-			return true
+// droppedAttributes returns the attributes of a feature that are dropped by
+// the attribute filter
+func droppedAttributes(feature string, names []string, a attributeConfig, f *labelfilter.Filter) []source.DroppedFeature {
+	dropped := []source.DroppedFeature{}
+	for _, name := range names {
+		if f.Check(name) == nil {
+			continue
 		}
-	} else {
-		if _, ok := f.keys[k]; !ok {
-			return true
+		reason := "listed in " + feature + ".attributeBlacklist"
+		if len(a.AttributeWhitelist) > 0 {
+			reason = "not listed in " + feature + ".attributeWhitelist"
 		}
+		dropped = append(dropped, source.DroppedFeature{Name: feature + "." + name, Reason: reason})
 	}
-	return false
+	return dropped
 }
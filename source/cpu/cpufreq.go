@@ -0,0 +1,76 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpu
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/node-feature-discovery/source"
+)
+
+// valueMixed is reported for settings that differ between CPUs
+const valueMixed = "mixed"
+
+// detectCpufreq detects the cpufreq scaling driver and governor. Settings of
+// all cpufreq policies are combined, a setting that differs between policies
+// is reported as "mixed".
+func detectCpufreq() (map[string]string, error) {
+	features := map[string]string{}
+
+	policies, err := source.Glob(source.SysfsDir.Path("devices/system/cpu/cpufreq/policy*"))
+	if err != nil {
+		return nil, err
+	}
+	for _, attr := range []struct {
+		file    string
+		feature string
+	}{
+		{"scaling_driver", "driver"},
+		{"scaling_governor", "governor"},
+	} {
+		values := []string{}
+		for _, p := range policies {
+			data, err := source.ReadFile(filepath.Join(p, attr.file))
+			if os.IsNotExist(err) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			values = append(values, strings.TrimSpace(string(data)))
+		}
+		if v := combineValues(values); v != "" {
+			features[attr.feature] = v
+		}
+	}
+	return features, nil
+}
+
+// combineValues returns the value shared by all the values, "mixed" if they
+// differ or an empty string if there are no values
+func combineValues(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	for _, v := range values[1:] {
+		if v != values[0] {
+			return valueMixed
+		}
+	}
+	return values[0]
+}
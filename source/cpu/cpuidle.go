@@ -0,0 +1,73 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpu
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/node-feature-discovery/source"
+)
+
+// detectCpuidle detects the cpuidle driver and whether each idle state is
+// enabled. A state that is enabled on some CPUs and disabled on others is
+// reported as "mixed".
+func detectCpuidle() (map[string]string, error) {
+	features := map[string]string{}
+
+	data, err := source.ReadFile(source.SysfsDir.Path("devices/system/cpu/cpuidle/current_driver"))
+	if os.IsNotExist(err) {
+		// cpuidle is not supported
+		return features, nil
+	} else if err != nil {
+		return nil, err
+	}
+	features["driver"] = strings.TrimSpace(string(data))
+
+	states, err := source.Glob(source.SysfsDir.Path("bus/cpu/devices/*/cpuidle/state*"))
+	if err != nil {
+		return nil, err
+	}
+	values := map[string][]string{}
+	names := []string{}
+	for _, state := range states {
+		data, err := source.ReadFile(filepath.Join(state, "name"))
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimSpace(string(data))
+
+		data, err = source.ReadFile(filepath.Join(state, "disable"))
+		if err != nil {
+			return nil, err
+		}
+		value := "enabled"
+		if strings.TrimSpace(string(data)) != "0" {
+			value = "disabled"
+		}
+
+		if _, ok := values[name]; !ok {
+			names = append(names, name)
+		}
+		values[name] = append(values[name], value)
+	}
+	for _, name := range names {
+		features["state."+name] = combineValues(values[name])
+	}
+	return features, nil
+}
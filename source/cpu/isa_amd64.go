@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpu
+
+import (
+	"strconv"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/cpuid"
+)
+
+const (
+	// CPUID EAX input values
+	LEAF_EXT_MAX_FUNCTION      = 0x80000000
+	LEAF_EXT_PROCESSOR_VERSION = 0x80000001
+
+	// CPUID bitmasks
+	PROCESSOR_VERSION_ECX_MOVBE       = 1 << 22
+	PROCESSOR_VERSION_ECX_OSXSAVE     = 1 << 27
+	EXT_PROCESSOR_VERSION_ECX_LAHF_LM = 1 << 0
+)
+
+// x86-64 microarchitecture levels, as defined in the x86-64 psABI, and the
+// CPU flags required by each of them on top of the previous level
+var x86Levels = [][]string{
+	{"CMOV", "MMX", "SSE", "SSE2"},
+	{"CX16", "LAHF_LM", "POPCNT", "SSE3", "SSE4.1", "SSE4.2", "SSSE3"},
+	{"AVX", "AVX2", "BMI1", "BMI2", "F16C", "FMA3", "LZCNT", "MOVBE", "OSXSAVE"},
+	{"AVX512F", "AVX512BW", "AVX512CD", "AVX512DQ", "AVX512VL"},
+}
+
+// discoverISA returns the highest x86-64 microarchitecture level supported by
+// the CPU
+func discoverISA(flags []string) map[string]string {
+	// Flags not detected by the cpuid library are read directly
+	all := map[string]bool{}
+	for _, f := range flags {
+		all[f] = true
+	}
+	version := cpuid.Cpuid(LEAF_PROCESSOR_VERSION, 0)
+	all["MOVBE"] = version.ECX&PROCESSOR_VERSION_ECX_MOVBE != 0
+	all["OSXSAVE"] = version.ECX&PROCESSOR_VERSION_ECX_OSXSAVE != 0
+	if cpuid.Cpuid(LEAF_EXT_MAX_FUNCTION, 0).EAX >= LEAF_EXT_PROCESSOR_VERSION {
+		extVersion := cpuid.Cpuid(LEAF_EXT_PROCESSOR_VERSION, 0)
+		all["LAHF_LM"] = extVersion.ECX&EXT_PROCESSOR_VERSION_ECX_LAHF_LM != 0
+	}
+
+	level := 0
+	for _, required := range x86Levels {
+		for _, f := range required {
+			if !all[f] {
+				return isaLevel(level)
+			}
+		}
+		level++
+	}
+	return isaLevel(level)
+}
+
+func isaLevel(level int) map[string]string {
+	if level == 0 {
+		return map[string]string{}
+	}
+	return map[string]string{"x86_64_level": strconv.Itoa(level)}
+}
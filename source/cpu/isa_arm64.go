@@ -0,0 +1,58 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpu
+
+// Armv8-A architecture revisions and the CPU flags, as reported in HWCAP,
+// made mandatory by each of them on top of the previous revision. Only the
+// revisions whose mandatory features are all visible in HWCAP are detected.
+var arm64Revisions = []struct {
+	revision string
+	flags    []string
+}{
+	{"8.0", []string{"FP", "ASIMD"}},
+	{"8.1", []string{"ATOMICS", "ASIMDRDM", "CRC32"}},
+	{"8.2", []string{"DCPOP"}},
+	{"8.3", []string{"JSCVT", "FCMA", "LRCPC"}},
+	{"8.4", []string{"DIT", "USCAT", "ILRCPC", "FLAGM"}},
+}
+
+// discoverISA returns the highest Armv8-A architecture revision supported by
+// the CPU
+func discoverISA(flags []string) map[string]string {
+	all := map[string]bool{}
+	for _, f := range flags {
+		all[f] = true
+	}
+
+	revision := ""
+	for _, r := range arm64Revisions {
+		for _, f := range r.flags {
+			if !all[f] {
+				return isaRevision(revision)
+			}
+		}
+		revision = r.revision
+	}
+	return isaRevision(revision)
+}
+
+func isaRevision(revision string) map[string]string {
+	if revision == "" {
+		return map[string]string{}
+	}
+	return map[string]string{"arm64_revision": revision}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpu
+
+// Fields of the CPU model, in the order they are published
+var cpuModelFields = []string{"vendor_id", "family", "id", "stepping"}
+
+func isValidModelLabelField(field string) bool {
+	for _, f := range cpuModelFields {
+		if field == f {
+			return true
+		}
+	}
+	return false
+}
+
+// modelFeatures returns the fields of the CPU model enabled in the config
+func modelFeatures(model map[string]string, fields []string) map[string]string {
+	enabled := map[string]bool{}
+	for _, f := range fields {
+		enabled[f] = true
+	}
+
+	features := map[string]string{}
+	for _, f := range cpuModelFields {
+		if v := model[f]; enabled[f] && v != "" {
+			features[f] = v
+		}
+	}
+	return features
+}
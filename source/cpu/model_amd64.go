@@ -0,0 +1,63 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpu
+
+import (
+	"encoding/binary"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/cpuid"
+)
+
+const (
+	// CPUID EAX input values
+	LEAF_VENDOR_ID         = 0x00
+	LEAF_PROCESSOR_VERSION = 0x01
+)
+
+// getCPUModel returns the CPU vendor ID, family, model and stepping read from
+// CPUID
+func getCPUModel() (map[string]string, error) {
+	// Vendor ID string is stored in EBX, EDX and ECX, in that order
+	vendor := cpuid.Cpuid(LEAF_VENDOR_ID, 0)
+	buf := make([]byte, 12)
+	binary.LittleEndian.PutUint32(buf[0:], vendor.EBX)
+	binary.LittleEndian.PutUint32(buf[4:], vendor.EDX)
+	binary.LittleEndian.PutUint32(buf[8:], vendor.ECX)
+	vendorID := strings.Join(strings.Fields(strings.Trim(string(buf), "\x00")), "_")
+
+	// Family and model are extended as described in the Intel and AMD
+	// programming manuals
+	version := cpuid.Cpuid(LEAF_PROCESSOR_VERSION, 0).EAX
+	stepping := version & 0xf
+	model := (version >> 4) & 0xf
+	family := (version >> 8) & 0xf
+	if family == 0x6 || family == 0xf {
+		model += ((version >> 16) & 0xf) << 4
+	}
+	if family == 0xf {
+		family += (version >> 20) & 0xff
+	}
+
+	return map[string]string{
+		"vendor_id": vendorID,
+		"family":    strconv.FormatUint(uint64(family), 10),
+		"id":        strconv.FormatUint(uint64(model), 10),
+		"stepping":  strconv.FormatUint(uint64(stepping), 10),
+	}, nil
+}
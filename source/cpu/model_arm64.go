@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpu
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/node-feature-discovery/source"
+)
+
+// getCPUModel returns the CPU implementer, architecture, part number and
+// revision, read from the Main ID Register (MIDR) of the first CPU or, if
+// that is not available, from /proc/cpuinfo
+func getCPUModel() (map[string]string, error) {
+	data, err := source.ReadFile(source.SysfsDir.Path("devices/system/cpu/cpu0/regs/identification/midr_el1"))
+	if err == nil {
+		midr, err := strconv.ParseUint(strings.TrimSpace(string(data)), 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse MIDR: %v", err)
+		}
+		arch := (midr >> 16) & 0xf
+		if arch == 0xf {
+			// Architectural features are identified by the ID registers,
+			// reported as ARMv8 by the kernel, too
+			arch = 8
+		}
+		return map[string]string{
+			"vendor_id": fmt.Sprintf("0x%02x", (midr>>24)&0xff),
+			"family":    strconv.FormatUint(arch, 10),
+			"id":        fmt.Sprintf("0x%03x", (midr>>4)&0xfff),
+			"stepping":  fmt.Sprintf("r%dp%d", (midr>>20)&0xf, midr&0xf),
+		}, nil
+	}
+
+	data, err = source.ReadFile(source.ProcfsDir.Path("cpuinfo"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cpuinfo: %v", err)
+	}
+	info := map[string]string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		split := strings.SplitN(line, ":", 2)
+		if len(split) != 2 {
+			continue
+		}
+		// Only the first CPU is inspected
+		key := strings.TrimSpace(split[0])
+		if _, ok := info[key]; !ok {
+			info[key] = strings.TrimSpace(split[1])
+		}
+	}
+
+	model := map[string]string{
+		"vendor_id": info["CPU implementer"],
+		"family":    info["CPU architecture"],
+		"id":        info["CPU part"],
+	}
+	variant, err := strconv.ParseUint(info["CPU variant"], 0, 8)
+	if err == nil {
+		revision, err := strconv.ParseUint(info["CPU revision"], 0, 8)
+		if err == nil {
+			model["stepping"] = fmt.Sprintf("r%dp%d", variant, revision)
+		}
+	}
+	return model, nil
+}
@@ -18,10 +18,11 @@ package cpu
 
 import (
 	"fmt"
-	"io/ioutil"
 	"os"
 	"strconv"
 	"strings"
+	"syscall"
+	"unsafe"
 
 	"sigs.k8s.io/node-feature-discovery/pkg/cpuid"
 	"sigs.k8s.io/node-feature-discovery/source"
@@ -30,15 +31,43 @@ import (
 const (
 	// CPUID EAX input values
 	LEAF_PROCESSOR_FREQUENCY_INFORMATION = 0x16
+
+	// ISST_IF_MBOX_COMMAND ioctl of the Intel Speed Select interface driver,
+	// see linux/isst_if.h
+	ISST_IF_MBOX_COMMAND = 0xc008fe03
+
+	// Intel Speed Select mailbox commands and sub-commands
+	SST_CONFIG_TDP                 = 0x7f
+	SST_CONFIG_TDP_GET_LEVELS_INFO = 0x00
+	SST_CONFIG_TDP_GET_TDP_CONTROL = 0x03
+	SST_READ_PM_CONFIG             = 0x94
+	SST_PM_FEATURE                 = 0x03
+
+	// Intel Speed Select mailbox response bitmasks
+	SST_TDP_CONTROL_FACT_ENABLED = 1 << 16
+	SST_PM_FEATURE_CP_ENABLED    = 1 << 16
 )
 
+// isstMboxCmds is struct isst_if_mbox_cmds, holding one command
+type isstMboxCmds struct {
+	CmdCount   uint32
+	Reserved   uint32
+	LogicalCPU uint32
+	Parameter  uint32
+	ReqData    uint32
+	RespData   uint32
+	Command    uint16
+	SubCommand uint16
+	Reserved2  uint32
+}
+
 func discoverSSTBF() (bool, error) {
 	// Get processor's "nominal base frequency" (in MHz) from CPUID
 	freqInfo := cpuid.Cpuid(LEAF_PROCESSOR_FREQUENCY_INFORMATION, 0)
 	nominalBaseFrequency := int(freqInfo.EAX)
 
 	// Loop over all CPUs in the system
-	files, err := ioutil.ReadDir(source.SysfsDir.Path("bus/cpu/devices"))
+	files, err := source.ReadDir(source.SysfsDir.Path("bus/cpu/devices"))
 
 	if err != nil {
 		return false, err
@@ -46,7 +75,7 @@ func discoverSSTBF() (bool, error) {
 	for _, file := range files {
 		// Try to read effective base frequency of each cpu in the system
 		filePath := source.SysfsDir.Path("bus/cpu/devices", file.Name(), "cpufreq/base_frequency")
-		data, err := ioutil.ReadFile(filePath)
+		data, err := source.ReadFile(filePath)
 		if os.IsNotExist(err) {
 			// Ignore missing file and continue to check other CPUs
 			continue
@@ -75,3 +104,53 @@ func discoverSSTBF() (bool, error) {
 
 	return false, nil
 }
+
+// discoverSST detects whether Intel SST-CP (Core Power) and SST-TF (Turbo
+// Frequency) are enabled, using the mailbox of the Intel Speed Select
+// interface driver
+func discoverSST() (map[string]bool, error) {
+	features := map[string]bool{}
+
+	f, err := source.OpenFile(source.DevDir.Path("isst_interface"), os.O_RDWR, 0)
+	if os.IsNotExist(err) {
+		// The driver is not loaded, or SST is not supported
+		return features, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	resp, err := isstMboxCommand(f, SST_READ_PM_CONFIG, SST_PM_FEATURE, 0)
+	if err == syscall.ENOTTY {
+		// Not the Intel Speed Select interface, e.g. in a snapshot
+		return features, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read SST-CP state: %v", err)
+	}
+	features["sst_cp.enabled"] = resp&SST_PM_FEATURE_CP_ENABLED != 0
+
+	// SST-TF is configured per performance profile, check the current one
+	resp, err = isstMboxCommand(f, SST_CONFIG_TDP, SST_CONFIG_TDP_GET_LEVELS_INFO, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SST-PP level: %v", err)
+	}
+	level := (resp >> 16) & 0xff
+	resp, err = isstMboxCommand(f, SST_CONFIG_TDP, SST_CONFIG_TDP_GET_TDP_CONTROL, level)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SST-TF state: %v", err)
+	}
+	features["sst_tf.enabled"] = resp&SST_TDP_CONTROL_FACT_ENABLED != 0
+
+	return features, nil
+}
+
+// isstMboxCommand sends one mailbox command to the first CPU and returns the
+// response
+func isstMboxCommand(f *os.File, command, subCommand uint16, parameter uint32) (uint32, error) {
+	cmds := isstMboxCmds{CmdCount: 1, Command: command, SubCommand: subCommand, Parameter: parameter}
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), ISST_IF_MBOX_COMMAND, uintptr(unsafe.Pointer(&cmds)))
+	if errno != 0 {
+		return 0, errno
+	}
+	return cmds.RespData, nil
+}
@@ -21,3 +21,7 @@ package cpu
 func discoverSSTBF() (bool, error) {
 	return false, nil
 }
+
+func discoverSST() (map[string]bool, error) {
+	return map[string]bool{}, nil
+}
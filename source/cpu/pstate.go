@@ -18,29 +18,43 @@ package cpu
 
 import (
 	"fmt"
-	"io/ioutil"
-	"runtime"
+	"os"
+	"strings"
 
 	"sigs.k8s.io/node-feature-discovery/source"
 )
 
 // Discover p-state related features such as turbo boost.
 func detectPstate() (map[string]string, error) {
-	// On other platforms, the frequency boost mechanism is software-based.
-	// So skip pstate detection on other architectures.
-	if runtime.GOARCH != "amd64" && runtime.GOARCH != "386" {
-		return nil, nil
+	// Turbo is controlled by intel_pstate, or by the cpufreq boost setting
+	// of other drivers, such as acpi-cpufreq and amd-pstate
+	turbo, err := readTurboSetting("devices/system/cpu/intel_pstate/no_turbo", "0")
+	if os.IsNotExist(err) {
+		turbo, err = readTurboSetting("devices/system/cpu/cpufreq/boost", "1")
 	}
-
-	// Only looking for turbo boost for now...
-	bytes, err := ioutil.ReadFile(source.SysfsDir.Path("devices/system/cpu/intel_pstate/no_turbo"))
-	if err != nil {
+	if os.IsNotExist(err) {
+		turbo, err = readTurboSetting("devices/system/cpu/cpufreq/policy0/boost", "1")
+	}
+	if os.IsNotExist(err) {
+		// Frequency boost is not supported or not controllable
+		return nil, nil
+	} else if err != nil {
 		return nil, fmt.Errorf("can't detect whether turbo boost is enabled: %s", err.Error())
 	}
+
 	features := map[string]string{"turbo": "false"}
-	if bytes[0] == byte('0') {
+	if turbo {
 		features["turbo"] = "true"
 	}
-
 	return features, nil
 }
+
+// readTurboSetting returns true if the content of a sysfs file equals the
+// value that denotes turbo boost being enabled
+func readTurboSetting(path, enabled string) (bool, error) {
+	data, err := source.ReadFile(source.SysfsDir.Path(path))
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(string(data)) == enabled, nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpu
+
+import (
+	"fmt"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/node-feature-discovery/source"
+)
+
+// discoverResctrl reads the details of the RDT resources from the resctrl
+// filesystem. No features are returned if resctrl is not mounted.
+func discoverResctrl() (source.Features, error) {
+	features := source.Features{}
+
+	dir := source.SysfsDir.Path("fs/resctrl/info")
+	resources, err := source.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return features, nil
+	} else if err != nil {
+		return nil, err
+	}
+	features["resctrl.mounted"] = true
+
+	for _, r := range resources {
+		name := strings.ToLower(r.Name())
+		path := filepath.Join(dir, r.Name())
+
+		// Monitoring resources, e.g. L3_MON, have no classes of service
+		if v, err := readResctrlInt(path, "num_closids"); err != nil {
+			return nil, err
+		} else if v >= 0 {
+			features[name+".num_closids"] = v
+		}
+
+		if data, err := source.ReadFile(filepath.Join(path, "cbm_mask")); err == nil {
+			mask, err := strconv.ParseUint(strings.TrimSpace(string(data)), 16, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cbm_mask of resctrl resource %s: %v", r.Name(), err)
+			}
+			features[name+".cache_ways"] = bits.OnesCount64(mask)
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		for _, attr := range []string{"bandwidth_gran", "min_bandwidth"} {
+			if v, err := readResctrlInt(path, attr); err != nil {
+				return nil, err
+			} else if v >= 0 {
+				features[name+"."+attr] = v
+			}
+		}
+	}
+	return features, nil
+}
+
+// readResctrlInt reads an integer attribute of a resctrl resource, returning
+// -1 if the resource does not have the attribute
+func readResctrlInt(path, attr string) (int, error) {
+	data, err := source.ReadFile(filepath.Join(path, attr))
+	if os.IsNotExist(err) {
+		return -1, nil
+	} else if err != nil {
+		return -1, err
+	}
+	v, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return -1, fmt.Errorf("invalid %s of resctrl resource %s: %v", attr, filepath.Base(path), err)
+	}
+	return v, nil
+}
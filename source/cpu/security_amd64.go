@@ -0,0 +1,133 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpu
+
+import (
+	"strings"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/cpuid"
+	"sigs.k8s.io/node-feature-discovery/source"
+)
+
+const (
+	// CPUID EAX input values
+	LEAF_SGX_CAPABILITIES      = 0x12
+	LEAF_AMD_MEMORY_ENCRYPTION = 0x8000001f
+
+	// CPUID ECX input values
+	SGX_CAPABILITIES_SUBLEAF_EPC = 2
+
+	// CPUID bitmasks
+	EXT_FEATURE_FLAGS_EBX_SGX         = 1 << 2
+	EXT_FEATURE_FLAGS_ECX_SGX_LC      = 1 << 30
+	SGX_CAPABILITIES_EAX_SGX1         = 1 << 0
+	SGX_EPC_EAX_TYPE_MASK             = 0xf
+	SGX_EPC_TYPE_SECTION              = 1
+	AMD_MEMORY_ENCRYPTION_EAX_SEV     = 1 << 1
+	AMD_MEMORY_ENCRYPTION_EAX_SEV_ES  = 1 << 3
+	AMD_MEMORY_ENCRYPTION_EAX_SEV_SNP = 1 << 4
+
+	// Maximum number of SGX EPC sections to enumerate
+	maxEPCSections = 64
+)
+
+// discoverSecurity detects confidential computing technologies that are
+// supported by the CPU and enabled on the host
+func discoverSecurity() source.Features {
+	features := source.Features{}
+
+	maxLeaf := cpuid.Cpuid(LEAF_VENDOR_ID, 0).EAX
+	maxExtLeaf := cpuid.Cpuid(LEAF_EXT_MAX_FUNCTION, 0).EAX
+
+	// Intel SGX
+	if maxLeaf >= LEAF_SGX_CAPABILITIES && sgxEnabled() {
+		features["sgx.enabled"] = true
+		if cpuid.Cpuid(LEAF_EXT_FEATURE_FLAGS, 0).ECX&EXT_FEATURE_FLAGS_ECX_SGX_LC != 0 {
+			features["sgx.flc"] = true
+		}
+		if epc := sgxEPCSize(); epc > 0 {
+			features["sgx.epc"] = epc
+		}
+	}
+
+	// Intel TDX
+	if moduleParamEnabled("kvm_intel", "tdx") {
+		features["tdx.enabled"] = true
+	}
+
+	// AMD SEV, SEV-ES and SEV-SNP
+	if maxExtLeaf >= LEAF_AMD_MEMORY_ENCRYPTION {
+		memEncryption := cpuid.Cpuid(LEAF_AMD_MEMORY_ENCRYPTION, 0)
+		if memEncryption.EAX&AMD_MEMORY_ENCRYPTION_EAX_SEV != 0 &&
+			moduleParamEnabled("kvm_amd", "sev") && deviceExists("sev") {
+			features["sev.enabled"] = true
+
+			if memEncryption.EAX&AMD_MEMORY_ENCRYPTION_EAX_SEV_ES != 0 && moduleParamEnabled("kvm_amd", "sev_es") {
+				features["sev.es.enabled"] = true
+			}
+			if memEncryption.EAX&AMD_MEMORY_ENCRYPTION_EAX_SEV_SNP != 0 && moduleParamEnabled("kvm_amd", "sev_snp") {
+				features["sev.snp.enabled"] = true
+			}
+		}
+	}
+
+	return features
+}
+
+// sgxEnabled returns true if SGX is supported by the CPU, enabled in the BIOS
+// and by the kernel
+func sgxEnabled() bool {
+	if cpuid.Cpuid(LEAF_EXT_FEATURE_FLAGS, 0).EBX&EXT_FEATURE_FLAGS_EBX_SGX == 0 {
+		return false
+	}
+	if cpuid.Cpuid(LEAF_SGX_CAPABILITIES, 0).EAX&SGX_CAPABILITIES_EAX_SGX1 == 0 {
+		return false
+	}
+	// Device node of the in-kernel driver, its earlier versions, or the
+	// out-of-tree driver
+	return deviceExists("sgx_enclave") || deviceExists("sgx/enclave") || deviceExists("isgx")
+}
+
+// sgxEPCSize returns the total size of the SGX Enclave Page Cache in bytes
+func sgxEPCSize() uint64 {
+	size := uint64(0)
+	for i := uint32(0); i < maxEPCSections; i++ {
+		section := cpuid.Cpuid(LEAF_SGX_CAPABILITIES, SGX_CAPABILITIES_SUBLEAF_EPC+i)
+		if section.EAX&SGX_EPC_EAX_TYPE_MASK != SGX_EPC_TYPE_SECTION {
+			break
+		}
+		// Bits 12-31 of the size are in ECX, bits 32-51 in EDX
+		size += uint64(section.ECX&0xfffff000) | uint64(section.EDX&0xfffff)<<32
+	}
+	return size
+}
+
+// moduleParamEnabled returns true if a boolean kernel module parameter is set
+func moduleParamEnabled(module, param string) bool {
+	data, err := source.ReadFile(source.SysfsDir.Path("module", module, "parameters", param))
+	if err != nil {
+		return false
+	}
+	v := strings.TrimSpace(string(data))
+	return v == "Y" || v == "1"
+}
+
+// deviceExists returns true if a device node exists
+func deviceExists(name string) bool {
+	_, err := source.Stat(source.DevDir.Path(name))
+	return err == nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpu
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/node-feature-discovery/source"
+)
+
+// cpuTopology describes the topology of the online CPUs of the system
+type cpuTopology struct {
+	Sockets        int
+	Cores          int
+	CoresPerSocket int
+	ThreadsPerCore int
+	OfflineCPUs    bool
+}
+
+// physicalCore identifies one core of the system
+type physicalCore struct {
+	socket int
+	core   int
+}
+
+// discoverTopology reads the topology of the CPUs from sysfs
+func discoverTopology() (*cpuTopology, error) {
+	files, err := source.ReadDir(source.SysfsDir.Path("bus/cpu/devices"))
+	if err != nil {
+		return nil, err
+	}
+
+	threads := map[physicalCore]int{}
+	cores := map[int]map[int]struct{}{}
+	for _, file := range files {
+		topologyDir := source.SysfsDir.Path("bus/cpu/devices", file.Name(), "topology")
+		if _, err := source.Stat(topologyDir); os.IsNotExist(err) {
+			// Topology is not available for offline CPUs
+			continue
+		}
+		socket, err := readTopologyID(topologyDir, "physical_package_id")
+		if err != nil {
+			return nil, err
+		}
+		core, err := readTopologyID(topologyDir, "core_id")
+		if err != nil {
+			return nil, err
+		}
+
+		threads[physicalCore{socket, core}]++
+		if _, ok := cores[socket]; !ok {
+			cores[socket] = map[int]struct{}{}
+		}
+		cores[socket][core] = struct{}{}
+	}
+
+	if len(threads) == 0 {
+		return nil, fmt.Errorf("no CPU topology information found")
+	}
+
+	topology := &cpuTopology{Sockets: len(cores), Cores: len(threads)}
+	for _, c := range cores {
+		if len(c) > topology.CoresPerSocket {
+			topology.CoresPerSocket = len(c)
+		}
+	}
+	for _, t := range threads {
+		if t > topology.ThreadsPerCore {
+			topology.ThreadsPerCore = t
+		}
+	}
+
+	// The list of offline CPUs is empty if all CPUs are online
+	offline, err := source.ReadFile(source.SysfsDir.Path("devices/system/cpu/offline"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	topology.OfflineCPUs = len(strings.TrimSpace(string(offline))) > 0
+
+	return topology, nil
+}
+
+// readTopologyID reads one topology ID of a CPU
+func readTopologyID(topologyDir, name string) (int, error) {
+	data, err := source.ReadFile(topologyDir + "/" + name)
+	if err != nil {
+		return 0, err
+	}
+	id, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("non-integer value of %q: %v", topologyDir+"/"+name, err)
+	}
+	return id, nil
+}
+
+// Check if any (online) CPUs have thread siblings
+func haveThreadSiblings() (bool, error) {
+
+	files, err := source.ReadDir(source.SysfsDir.Path("bus/cpu/devices"))
+	if err != nil {
+		return false, err
+	}
+
+	for _, file := range files {
+		// Try to read siblings from topology
+		siblings, err := source.ReadFile(source.SysfsDir.Path("bus/cpu/devices", file.Name(), "topology/thread_siblings_list"))
+		if err != nil {
+			return false, err
+		}
+		for _, char := range siblings {
+			// If list separator found, we determine that there are multiple siblings
+			if char == ',' || char == '-' {
+				return true, nil
+			}
+		}
+	}
+	// No siblings were found
+	return false, nil
+}
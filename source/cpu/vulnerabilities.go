@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpu
+
+import (
+	"os"
+	"strings"
+
+	"sigs.k8s.io/node-feature-discovery/source"
+)
+
+// Normalized statuses of CPU vulnerabilities
+const (
+	vulnerabilityNotAffected = "not_affected"
+	vulnerabilityMitigated   = "mitigated"
+	vulnerabilityVulnerable  = "vulnerable"
+	vulnerabilityUnknown     = "unknown"
+)
+
+// vulnerability is the status of one CPU vulnerability
+type vulnerability struct {
+	Status string
+	// SMTVulnerable is true if the system is vulnerable when SMT is enabled,
+	// even if the vulnerability is otherwise mitigated
+	SMTVulnerable bool
+}
+
+// discoverVulnerabilities reads the status of CPU vulnerabilities, as
+// reported by the kernel, keyed by the name of the vulnerability
+func discoverVulnerabilities() (map[string]vulnerability, error) {
+	vulnerabilities := map[string]vulnerability{}
+
+	dir := source.SysfsDir.Path("devices/system/cpu/vulnerabilities")
+	files, err := source.ReadDir(dir)
+	if os.IsNotExist(err) {
+		// Not supported by older kernels
+		return vulnerabilities, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		data, err := source.ReadFile(dir + "/" + file.Name())
+		if err != nil {
+			return nil, err
+		}
+		vulnerabilities[file.Name()] = parseVulnerability(string(data))
+	}
+	return vulnerabilities, nil
+}
+
+// parseVulnerability normalizes the status string reported by the kernel
+func parseVulnerability(s string) vulnerability {
+	// Statuses of the KVM specific vulnerabilities are prefixed with "KVM: "
+	s = strings.TrimPrefix(strings.TrimSpace(s), "KVM: ")
+
+	v := vulnerability{SMTVulnerable: strings.Contains(s, "SMT vulnerable")}
+	switch {
+	case strings.HasPrefix(s, "Not affected"):
+		v.Status = vulnerabilityNotAffected
+	case strings.HasPrefix(s, "Mitigation"):
+		v.Status = vulnerabilityMitigated
+	case strings.HasPrefix(s, "Vulnerable"), strings.HasPrefix(s, "Processor vulnerable"):
+		v.Status = vulnerabilityVulnerable
+	default:
+		v.Status = vulnerabilityUnknown
+	}
+	return v
+}
@@ -17,19 +17,29 @@ limitations under the License.
 package custom
 
 import (
-	"log"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
 
+	"sigs.k8s.io/node-feature-discovery/pkg/logger"
 	"sigs.k8s.io/node-feature-discovery/source"
 	"sigs.k8s.io/node-feature-discovery/source/custom/rules"
+	"sigs.k8s.io/node-feature-discovery/source/internal/kernelutils"
 )
 
+var log = logger.WithValues("source", "custom")
+
 // Custom Features Configurations
 type MatchRule struct {
-	PciID      *rules.PciIDRule      `json:"pciId,omitempty"`
-	UsbID      *rules.UsbIDRule      `json:"usbId,omitempty"`
-	LoadedKMod *rules.LoadedKModRule `json:"loadedKMod,omitempty"`
-	CpuID      *rules.CpuIDRule      `json:"cpuId,omitempty"`
-	Kconfig    *rules.KconfigRule    `json:"kConfig,omitempty"`
+	PciID         *rules.PciIDRule         `json:"pciId,omitempty"`
+	UsbID         *rules.UsbIDRule         `json:"usbId,omitempty"`
+	LoadedKMod    *rules.LoadedKModRule    `json:"loadedKMod,omitempty"`
+	AvailableKMod *rules.AvailableKModRule `json:"availableKMod,omitempty"`
+	CpuID         *rules.CpuIDRule         `json:"cpuId,omitempty"`
+	Kconfig       *rules.KconfigRule       `json:"kConfig,omitempty"`
+	Sysctl        *rules.SysctlRule        `json:"sysctl,omitempty"`
 }
 
 type FeatureSpec struct {
@@ -37,13 +47,102 @@ type FeatureSpec struct {
 	MatchOn []MatchRule `json:"matchOn"`
 }
 
-type config []FeatureSpec
+// config of the custom source. It is specified either as a list of features,
+// or as an object with the list of features in the features field, which
+// allows specifying a label filter, too.
+type config struct {
+	Features []FeatureSpec `json:"features"`
+
+	source.LabelFilterConfig
+}
 
 // newDefaultConfig returns a new config with pre-populated defaults
 func newDefaultConfig() *config {
 	return &config{}
 }
 
+// UnmarshalJSON implements the Unmarshaler interface from "encoding/json"
+func (c *config) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		c.LabelFilterConfig = source.LabelFilterConfig{}
+		return json.Unmarshal(data, &c.Features)
+	}
+
+	// Avoid recursing into this method
+	type objectConfig config
+	return json.Unmarshal(data, (*objectConfig)(c))
+}
+
+// Validate method of the ConfigValidator interface
+func (c *config) Validate() []error {
+	errs := []error{}
+	for i, spec := range c.Features {
+		if spec.Name == "" {
+			errs = append(errs, fmt.Errorf("feature #%d: name must not be empty", i))
+		}
+		if len(spec.MatchOn) == 0 {
+			errs = append(errs, fmt.Errorf("feature %q: no matchOn rules defined", spec.Name))
+		}
+		for j, rule := range spec.MatchOn {
+			for _, err := range rule.validate() {
+				errs = append(errs, fmt.Errorf("feature %q: matchOn[%d]: %v", spec.Name, j, err))
+			}
+		}
+	}
+	return errs
+}
+
+// validate checks a MatchRule for errors
+func (r MatchRule) validate() []error {
+	errs := []error{}
+	if r.PciID == nil && r.UsbID == nil && r.LoadedKMod == nil && r.AvailableKMod == nil && r.CpuID == nil && r.Kconfig == nil && r.Sysctl == nil {
+		errs = append(errs, fmt.Errorf("no rules defined"))
+	}
+	if r.PciID != nil {
+		errs = append(errs, validateIDs("pciId.class", r.PciID.Class, 4)...)
+		errs = append(errs, validateIDs("pciId.vendor", r.PciID.Vendor, 4)...)
+		errs = append(errs, validateIDs("pciId.device", r.PciID.Device, 4)...)
+	}
+	if r.UsbID != nil {
+		errs = append(errs, validateIDs("usbId.class", r.UsbID.Class, 2)...)
+		errs = append(errs, validateIDs("usbId.vendor", r.UsbID.Vendor, 4)...)
+		errs = append(errs, validateIDs("usbId.device", r.UsbID.Device, 4)...)
+	}
+	if r.AvailableKMod != nil {
+		for _, name := range *r.AvailableKMod {
+			if err := kernelutils.ValidateKmodName(name); err != nil {
+				errs = append(errs, fmt.Errorf("availableKMod: %v", err))
+			}
+		}
+	}
+	if r.Sysctl != nil {
+		keys := make([]string, 0, len(*r.Sysctl))
+		for key := range *r.Sysctl {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := kernelutils.ValidateSysctlKey(key); err != nil {
+				errs = append(errs, fmt.Errorf("sysctl: %v", err))
+			}
+		}
+	}
+	return errs
+}
+
+// validateIDs checks that all ids are lowercase hex numbers of the given
+// length, as they appear in sysfs
+func validateIDs(field string, ids []string, length int) []error {
+	errs := []error{}
+	re := regexp.MustCompile(fmt.Sprintf("^[0-9a-f]{%d}$", length))
+	for _, id := range ids {
+		if !re.MatchString(id) {
+			errs = append(errs, fmt.Errorf("invalid %s %q, must be %d lowercase hex digits", field, id, length))
+		}
+	}
+	return errs
+}
+
 // Implements FeatureSource Interface
 type Source struct {
 	config *config
@@ -64,20 +163,20 @@ func (s *Source) SetConfig(conf source.Config) {
 	case *config:
 		s.config = v
 	default:
-		log.Printf("PANIC: invalid config type: %T", conf)
+		log.Error(fmt.Errorf("invalid config type: %T", conf), "failed to configure source")
 	}
 }
 
 // Discover features
 func (s Source) Discover() (source.Features, error) {
 	features := source.Features{}
-	allFeatureConfig := append(getStaticFeatureConfig(), *s.config...)
-	log.Printf("INFO: Custom features: %+v", allFeatureConfig)
+	allFeatureConfig := append(getStaticFeatureConfig(), s.config.Features...)
+	log.V(2).Info("custom features", "features", fmt.Sprintf("%+v", allFeatureConfig))
 	// Iterate over features
 	for _, customFeature := range allFeatureConfig {
 		featureExist, err := s.discoverFeature(customFeature)
 		if err != nil {
-			log.Printf("ERROR: failed to discover feature: %q: %s", customFeature.Name, err.Error())
+			log.Error(err, "failed to discover feature", "feature", customFeature.Name)
 			continue
 		}
 		if featureExist {
@@ -87,6 +186,20 @@ func (s Source) Discover() (source.Features, error) {
 	return features, nil
 }
 
+// DroppedFeatures method of the FeatureExplainer interface
+func (s Source) DroppedFeatures() ([]source.DroppedFeature, error) {
+	dropped := []source.DroppedFeature{}
+	for _, customFeature := range append(getStaticFeatureConfig(), s.config.Features...) {
+		featureExist, err := s.discoverFeature(customFeature)
+		if err != nil {
+			dropped = append(dropped, source.DroppedFeature{Name: customFeature.Name, Reason: fmt.Sprintf("failed to evaluate rules: %v", err)})
+		} else if !featureExist {
+			dropped = append(dropped, source.DroppedFeature{Name: customFeature.Name, Reason: "none of the matchOn rules matched"})
+		}
+	}
+	return dropped, nil
+}
+
 // Process a single feature by Matching on the defined rules.
 // A feature is present if all defined Rules in a MatchRule return a match.
 func (s Source) discoverFeature(feature FeatureSpec) (bool, error) {
@@ -121,6 +234,16 @@ func (s Source) discoverFeature(feature FeatureSpec) (bool, error) {
 				continue
 			}
 		}
+		// Available kernel module rule
+		if rule.AvailableKMod != nil {
+			match, err := rule.AvailableKMod.Match()
+			if err != nil {
+				return false, err
+			}
+			if !match {
+				continue
+			}
+		}
 		// cpuid rule
 		if rule.CpuID != nil {
 			match, err := rule.CpuID.Match()
@@ -141,6 +264,16 @@ func (s Source) discoverFeature(feature FeatureSpec) (bool, error) {
 				continue
 			}
 		}
+		// sysctl rule
+		if rule.Sysctl != nil {
+			match, err := rule.Sysctl.Match()
+			if err != nil {
+				return false, err
+			}
+			if !match {
+				continue
+			}
+		}
 		return true, nil
 	}
 	return false, nil
@@ -0,0 +1,41 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/node-feature-discovery/source/internal/kernelutils"
+)
+
+// Rule that matches on kernel modules that are loaded, built into the kernel
+// or available to be loaded
+type AvailableKModRule []string
+
+// Match available kernel modules on provided list of kernel modules
+func (kmods *AvailableKModRule) Match() (bool, error) {
+	modules, err := kernelutils.GetKernelModules()
+	if err != nil {
+		return false, fmt.Errorf("failed to get kernel modules: %v", err)
+	}
+	for _, kmod := range *kmods {
+		if modules.Status(kmod) == kernelutils.KmodMissing {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -23,9 +23,12 @@ import (
 // CpuIDRule implements Rule
 type CpuIDRule []string
 
-var cpuIdFlags map[string]struct{}
-
 func (cpuids *CpuIDRule) Match() (bool, error) {
+	cpuIdFlags := make(map[string]struct{})
+	for _, f := range cpuidutils.GetCpuidFlags() {
+		cpuIdFlags[f] = struct{}{}
+	}
+
 	for _, f := range *cpuids {
 		if _, ok := cpuIdFlags[f]; !ok {
 			return false, nil
@@ -33,10 +36,3 @@ func (cpuids *CpuIDRule) Match() (bool, error) {
 	}
 	return true, nil
 }
-
-func init() {
-	cpuIdFlags = make(map[string]struct{})
-	for _, f := range cpuidutils.GetCpuidFlags() {
-		cpuIdFlags[f] = struct{}{}
-	}
-}
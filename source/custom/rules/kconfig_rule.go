@@ -18,15 +18,20 @@ package rules
 
 import (
 	"fmt"
+
 	"sigs.k8s.io/node-feature-discovery/source/internal/kernelutils"
 )
 
 // KconfigRule implements Rule
 type KconfigRule []string
 
-var kConfigs map[string]struct{}
-
 func (kconfigs *KconfigRule) Match() (bool, error) {
+	// An unavailable kernel config is treated as if no option was set
+	kConfigs, err := getKconfigs()
+	if err != nil {
+		return false, nil
+	}
+
 	for _, f := range *kconfigs {
 		if _, ok := kConfigs[f]; !ok {
 			return false, nil
@@ -35,17 +40,21 @@ func (kconfigs *KconfigRule) Match() (bool, error) {
 	return true, nil
 }
 
-func init() {
-	kConfigs = make(map[string]struct{})
-
+// getKconfigs returns the kernel config options of the host, options with a
+// value other than "y" or "m" in OPTION=VALUE form
+func getKconfigs() (map[string]struct{}, error) {
 	kconfig, err := kernelutils.ParseKconfig("")
-	if err == nil {
-		for k, v := range kconfig {
-			if v != "true" {
-				kConfigs[fmt.Sprintf("%s=%s", k, v)] = struct{}{}
-			} else {
-				kConfigs[k] = struct{}{}
-			}
+	if err != nil {
+		return nil, err
+	}
+
+	kConfigs := make(map[string]struct{}, len(kconfig))
+	for k, v := range kconfig {
+		if v != "true" {
+			kConfigs[fmt.Sprintf("%s=%s", k, v)] = struct{}{}
+		} else {
+			kConfigs[k] = struct{}{}
 		}
 	}
+	return kConfigs, nil
 }
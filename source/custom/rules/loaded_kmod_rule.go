@@ -18,15 +18,14 @@ package rules
 
 import (
 	"fmt"
-	"io/ioutil"
 	"strings"
+
+	"sigs.k8s.io/node-feature-discovery/source"
 )
 
 // Rule that matches on loaded kernel modules in the system
 type LoadedKModRule []string
 
-const kmodProcfsPath = "/proc/modules"
-
 // Match loaded kernel modules on provided list of kernel modules
 func (kmods *LoadedKModRule) Match() (bool, error) {
 	loadedModules, err := kmods.getLoadedModules()
@@ -43,7 +42,8 @@ func (kmods *LoadedKModRule) Match() (bool, error) {
 }
 
 func (kmods *LoadedKModRule) getLoadedModules() (map[string]struct{}, error) {
-	out, err := ioutil.ReadFile(kmodProcfsPath)
+	kmodProcfsPath := source.ProcfsDir.Path("modules")
+	out, err := source.ReadFile(kmodProcfsPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file %s: %s", kmodProcfsPath, err.Error())
 	}
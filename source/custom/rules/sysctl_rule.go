@@ -0,0 +1,46 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rules
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/node-feature-discovery/source/internal/kernelutils"
+)
+
+// SysctlRule matches on the values of kernel parameters, keyed by the sysctl
+// key
+type SysctlRule map[string]string
+
+// Match kernel parameters against the expected values. Fields of
+// multi-valued parameters are compared with any whitespace in between.
+func (sysctls *SysctlRule) Match() (bool, error) {
+	for key, expected := range *sysctls {
+		val, err := kernelutils.ReadSysctl(key)
+		if os.IsNotExist(err) {
+			return false, nil
+		} else if err != nil {
+			return false, fmt.Errorf("failed to read sysctl %s: %v", key, err)
+		}
+		if val != strings.Join(strings.Fields(expected), " ") {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// The feature sources access the host directories through the functions
+// below. They behave like their counterparts in the os, io/ioutil and
+// path/filepath packages, but can also record the paths being accessed, e.g.
+// for capturing a snapshot of the files read by the sources.
+
+// HostAccess is a path accessed by the feature sources
+type HostAccess struct {
+	Path string
+	// Content is true if the content of the file was read, false if only
+	// its existence was inspected
+	Content bool
+}
+
+var (
+	recordLock sync.Mutex
+	// recorded holds the paths accessed since StartRecording, nil if not
+	// recording
+	recorded map[string]bool
+)
+
+// StartRecording starts recording the host paths accessed by the feature
+// sources, discarding any previous recording
+func StartRecording() {
+	recordLock.Lock()
+	defer recordLock.Unlock()
+	recorded = map[string]bool{}
+}
+
+// StopRecording stops recording and returns the host paths accessed since
+// StartRecording, sorted by path
+func StopRecording() []HostAccess {
+	recordLock.Lock()
+	defer recordLock.Unlock()
+
+	accesses := make([]HostAccess, 0, len(recorded))
+	for p, content := range recorded {
+		accesses = append(accesses, HostAccess{Path: p, Content: content})
+	}
+	recorded = nil
+
+	sort.Slice(accesses, func(i, j int) bool { return accesses[i].Path < accesses[j].Path })
+	return accesses
+}
+
+// record adds accessed paths to the recording, if recording
+func record(content bool, paths ...string) {
+	recordLock.Lock()
+	defer recordLock.Unlock()
+	if recorded == nil {
+		return
+	}
+	for _, p := range paths {
+		recorded[p] = recorded[p] || content
+	}
+}
+
+// ReadFile reads the content of a host file
+func ReadFile(name string) ([]byte, error) {
+	record(true, name)
+	return ioutil.ReadFile(name)
+}
+
+// ReadDir reads the entries of a host directory, sorted by name
+func ReadDir(dirname string) ([]os.FileInfo, error) {
+	record(false, dirname)
+	entries, err := ioutil.ReadDir(dirname)
+	for _, e := range entries {
+		record(false, filepath.Join(dirname, e.Name()))
+	}
+	return entries, err
+}
+
+// Stat returns information about a host file, following symlinks
+func Stat(name string) (os.FileInfo, error) {
+	record(false, name)
+	return os.Stat(name)
+}
+
+// Open opens a host file for reading
+func Open(name string) (*os.File, error) {
+	record(true, name)
+	return os.Open(name)
+}
+
+// OpenFile opens a host file with the given flags, e.g. a device node
+func OpenFile(name string, flag int, perm os.FileMode) (*os.File, error) {
+	record(true, name)
+	return os.OpenFile(name, flag, perm)
+}
+
+// Glob returns the host paths matching a pattern
+func Glob(pattern string) ([]string, error) {
+	matches, err := filepath.Glob(pattern)
+	record(false, matches...)
+	return matches, err
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuidutils
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"unsafe"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/logger"
+)
+
+// Types of auxiliary vector entries, see linux/auxvec.h
+const (
+	AT_NULL   = 0
+	AT_HWCAP  = 16
+	AT_HWCAP2 = 26
+)
+
+// auxvPath is the auxiliary vector of the running process. The hardware
+// capabilities in it are the same for all processes of the host.
+var auxvPath = "/proc/self/auxv"
+
+// parseAuxv parses the raw auxiliary vector, i.e. a list of (type, value)
+// pairs of machine words terminated by AT_NULL, into a map keyed by type
+func parseAuxv(data []byte, wordSize int, order binary.ByteOrder) (map[uint64]uint64, error) {
+	if wordSize != 4 && wordSize != 8 {
+		return nil, fmt.Errorf("invalid word size %d", wordSize)
+	}
+	word := func(b []byte) uint64 {
+		if wordSize == 4 {
+			return uint64(order.Uint32(b))
+		}
+		return order.Uint64(b)
+	}
+
+	auxv := map[uint64]uint64{}
+	for len(data) >= 2*wordSize {
+		typ, val := word(data), word(data[wordSize:])
+		if typ == AT_NULL {
+			return auxv, nil
+		}
+		auxv[typ] = val
+		data = data[2*wordSize:]
+	}
+	return nil, fmt.Errorf("auxiliary vector not terminated by AT_NULL")
+}
+
+// readHwcap reads AT_HWCAP and AT_HWCAP2 from the auxiliary vector of the
+// running process
+func readHwcap() (uint64, uint64, error) {
+	data, err := ioutil.ReadFile(auxvPath)
+	if err != nil {
+		return 0, 0, err
+	}
+	auxv, err := parseAuxv(data, int(unsafe.Sizeof(uintptr(0))), nativeByteOrder())
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to parse %s: %v", auxvPath, err)
+	}
+	return auxv[AT_HWCAP], auxv[AT_HWCAP2], nil
+}
+
+// nativeByteOrder returns the byte order of the running CPU
+func nativeByteOrder() binary.ByteOrder {
+	x := uint16(1)
+	if *(*byte)(unsafe.Pointer(&x)) == 1 {
+		return binary.LittleEndian
+	}
+	return binary.BigEndian
+}
+
+// hwcapFlags returns the names of the bits set in hwcap and hwcap2, in bit
+// order. Bits without a name are ignored.
+func hwcapFlags(hwcap, hwcap2 uint64, names, names2 map[uint64]string) []string {
+	r := make([]string, 0, 30)
+	for _, h := range []struct {
+		value uint64
+		names map[uint64]string
+	}{
+		{hwcap, names},
+		{hwcap2, names2},
+	} {
+		for i := uint(0); i < 64; i++ {
+			key := uint64(1) << i
+			if val, ok := h.names[key]; ok && h.value&key != 0 {
+				r = append(r, val)
+			}
+		}
+	}
+	return r
+}
+
+// getHwcapFlags returns the names of the hardware capabilities of the running
+// CPU
+func getHwcapFlags(names, names2 map[uint64]string) []string {
+	hwcap, hwcap2, err := readHwcap()
+	if err != nil {
+		logger.Error(err, "failed to read hardware capabilities")
+		return []string{}
+	}
+	return hwcapFlags(hwcap, hwcap2, names, names2)
+}
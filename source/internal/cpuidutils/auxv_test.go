@@ -0,0 +1,114 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuidutils
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseAuxv(t *testing.T) {
+	Convey("When parsing an auxiliary vector", t, func() {
+		Convey("entries up to AT_NULL are returned", func() {
+			data := []byte{16, 0, 0, 0, 0xff, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 26, 0, 0, 0, 1, 0, 0, 0}
+			auxv, err := parseAuxv(data, 4, binary.LittleEndian)
+			So(err, ShouldBeNil)
+			So(auxv, ShouldResemble, map[uint64]uint64{AT_HWCAP: 0xff})
+		})
+
+		Convey("a vector without AT_NULL is rejected", func() {
+			_, err := parseAuxv([]byte{16, 0, 0, 0, 0xff, 0, 0, 0}, 4, binary.LittleEndian)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("an invalid word size is rejected", func() {
+			_, err := parseAuxv(make([]byte, 16), 2, binary.LittleEndian)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestHwcapFlags(t *testing.T) {
+	Convey("When reading hardware capabilities from auxiliary vectors", t, func() {
+		for _, tc := range []struct {
+			fixture  string
+			wordSize int
+			order    binary.ByteOrder
+			names    map[uint64]string
+			names2   map[uint64]string
+			expected []string
+		}{
+			{
+				fixture: "auxv_arm64", wordSize: 8, order: binary.LittleEndian,
+				names: flagNames_arm64, names2: flag2Names_arm64,
+				expected: []string{
+					"FP", "ASIMD", "EVTSTRM", "AES", "PMULL", "SHA1", "SHA2", "CRC32", "ATOMICS",
+					"FPHP", "ASIMDHP", "CPUID", "ASIMDRDM", "LRCPC", "DCPOP", "ASIMDDP", "SSBS",
+					"DCPODP", "SVE2", "SVEI8MM", "SVEBF16", "I8MM", "BF16", "RNG", "MTE",
+				},
+			},
+			{
+				fixture: "auxv_arm", wordSize: 4, order: binary.LittleEndian,
+				names: flagNames_arm, names2: flag2Names_arm,
+				expected: []string{
+					"HALF", "THUMB", "FASTMUL", "VFP", "EDSP", "NEON", "VFPv3", "TLS", "VFPv4",
+					"IDIVA", "IDIVT", "VFPD32", "LPAE", "EVTSTRM",
+					"AES", "PMULL", "SHA1", "SHA2", "CRC32",
+				},
+			},
+			{
+				fixture: "auxv_ppc64le", wordSize: 8, order: binary.LittleEndian,
+				names: flagNames_ppc64le, names2: flag2Names_ppc64le,
+				expected: []string{
+					"TRUE_LE", "VSX", "ARCH_2_06", "DFP", "ARCH_2_05", "IC_SNOOP", "SMT", "MMU",
+					"FPU", "ALTIVEC", "PPC64",
+					"DARN", "IEEE128", "ARCH_3_00", "VCRYPTO", "TAR", "ISEL", "EBB", "DSCR",
+					"HTM", "ARCH_2_07",
+				},
+			},
+			{
+				fixture: "auxv_s390x", wordSize: 8, order: binary.BigEndian,
+				names: flagNames_s390x,
+				expected: []string{
+					"ESAN3", "ZARCH", "STFLE", "MSA", "LDISP", "EIMM", "DFP", "EDAT", "ETF3EH",
+					"HIGHGPRS", "VX",
+				},
+			},
+		} {
+			Convey("the flags of "+tc.fixture+" are found", func() {
+				data, err := ioutil.ReadFile("testdata/" + tc.fixture)
+				So(err, ShouldBeNil)
+				auxv, err := parseAuxv(data, tc.wordSize, tc.order)
+				So(err, ShouldBeNil)
+				So(hwcapFlags(auxv[AT_HWCAP], auxv[AT_HWCAP2], tc.names, tc.names2), ShouldResemble, tc.expected)
+			})
+		}
+
+		Convey("bits without a name are ignored", func() {
+			So(hwcapFlags(1<<63, 1<<63, flagNames_arm64, flag2Names_arm64), ShouldResemble, []string{})
+		})
+
+		Convey("no flags are returned if the auxiliary vector cannot be read", func() {
+			auxvPath = "testdata/nonexistent"
+			defer func() { auxvPath = "/proc/self/auxv" }()
+			So(getHwcapFlags(flagNames_arm64, flag2Names_arm64), ShouldResemble, []string{})
+		})
+	})
+}
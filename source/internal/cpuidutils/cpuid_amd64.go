@@ -21,6 +21,6 @@ import (
 )
 
 // Discover returns feature names for all the supported CPU features.
-func GetCpuidFlags() []string {
+func getCpuidFlags() []string {
 	return cpuid.CPU.Features.Strings()
 }
@@ -0,0 +1,36 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cpuidutils
+
+// replayFlags, if non-nil, are reported instead of the flags of the running
+// CPU
+var replayFlags []string
+
+// GetCpuidFlags returns the feature flags of the CPU
+func GetCpuidFlags() []string {
+	if replayFlags != nil {
+		return replayFlags
+	}
+	return getCpuidFlags()
+}
+
+// SetReplayFlags makes GetCpuidFlags return the given flags, e.g. ones
+// captured on another node, instead of the flags of the running CPU. Nil
+// restores the default behavior.
+func SetReplayFlags(flags []string) {
+	replayFlags = flags
+}
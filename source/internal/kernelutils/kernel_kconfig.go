@@ -21,20 +21,19 @@ import (
 	"compress/gzip"
 	"fmt"
 	"io/ioutil"
-	"log"
-	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/node-feature-discovery/pkg/logger"
 	"sigs.k8s.io/node-feature-discovery/source"
 )
 
 // Read gzipped kernel config
 func readKconfigGzip(filename string) ([]byte, error) {
 	// Open file for reading
-	f, err := os.Open(filename)
+	f, err := source.Open(filename)
 	if err != nil {
 		return nil, err
 	}
@@ -60,21 +59,21 @@ func ParseKconfig(configPath string) (map[string]string, error) {
 	kVer, err := GetKernelVersion()
 	if err != nil {
 		searchPaths = []string{
-			"/proc/config.gz",
-			"/usr/src/linux/.config",
+			source.ProcfsDir.Path("config.gz"),
+			source.UsrSrcDir.Path("linux", ".config"),
 		}
 	} else {
 		// from k8s.io/system-validator used by kubeadm
 		// preflight checks
 		searchPaths = []string{
-			"/proc/config.gz",
-			"/usr/src/linux-" + kVer + "/.config",
-			"/usr/src/linux/.config",
-			"/usr/lib/modules/" + kVer + "/config",
-			"/usr/lib/ostree-boot/config-" + kVer,
-			"/usr/lib/kernel/config-" + kVer,
-			"/usr/src/linux-headers-" + kVer + "/.config",
-			"/lib/modules/" + kVer + "/build/.config",
+			source.ProcfsDir.Path("config.gz"),
+			source.UsrSrcDir.Path("linux-"+kVer, ".config"),
+			source.UsrSrcDir.Path("linux", ".config"),
+			source.UsrLibDir.Path("modules", kVer, "config"),
+			source.UsrLibDir.Path("ostree-boot", "config-"+kVer),
+			source.UsrLibDir.Path("kernel", "config-"+kVer),
+			source.UsrSrcDir.Path("linux-headers-"+kVer, ".config"),
+			source.LibDir.Path("modules", kVer, "build", ".config"),
 			source.BootDir.Path("config-" + kVer),
 		}
 	}
@@ -86,7 +85,7 @@ func ParseKconfig(configPath string) (map[string]string, error) {
 					break
 				}
 			} else {
-				if raw, err = ioutil.ReadFile(path); err == nil {
+				if raw, err = source.ReadFile(path); err == nil {
 					break
 				}
 			}
@@ -109,7 +108,7 @@ func ParseKconfig(configPath string) (map[string]string, error) {
 			} else {
 				value := strings.Trim(m[2], `"`)
 				if len(value) > validation.LabelValueMaxLength {
-					log.Printf("WARNING: ignoring kconfig option '%s': value exceeds max length of %d characters", m[1], validation.LabelValueMaxLength)
+					logger.Warning("ignoring kconfig option, value exceeds max length", "option", m[1], "maxLength", validation.LabelValueMaxLength)
 					continue
 				}
 				kconfig[m[1]] = value
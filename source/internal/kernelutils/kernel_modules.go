@@ -0,0 +1,148 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kernelutils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/node-feature-discovery/source"
+)
+
+// Kernel module states, from the most to the least available
+const (
+	KmodLoaded    = "loaded"
+	KmodBuiltin   = "builtin"
+	KmodAvailable = "available"
+	KmodMissing   = "missing"
+)
+
+var kmodNameRe = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// KernelModules holds the kernel modules of the running kernel
+type KernelModules struct {
+	loaded    map[string]struct{}
+	builtin   map[string]struct{}
+	available map[string]struct{}
+}
+
+// ValidateKmodName returns an error if the kernel module name is malformed
+func ValidateKmodName(name string) error {
+	if !kmodNameRe.MatchString(name) {
+		return fmt.Errorf("invalid kernel module name %q", name)
+	}
+	return nil
+}
+
+// KmodName normalizes a kernel module name. The kernel treats dashes and
+// underscores in module names the same.
+func KmodName(name string) string {
+	return strings.Replace(name, "-", "_", -1)
+}
+
+// GetKernelModules reads the loaded kernel modules from /proc/modules, and
+// the built-in and loadable modules from modules.builtin and modules.dep
+// under /lib/modules/<kernel version>, or /usr/lib/modules/<kernel version>
+// if the former does not exist. Missing module lists are treated as empty.
+func GetKernelModules() (*KernelModules, error) {
+	kVer, err := GetKernelVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	m := &KernelModules{}
+	if m.loaded, err = readKmodList(source.ProcfsDir.Path("modules"), " "); err != nil {
+		return nil, err
+	}
+	dir := modulesDir(kVer)
+	if m.builtin, err = readOptionalKmodList(filepath.Join(dir, "modules.builtin"), ""); err != nil {
+		return nil, err
+	}
+	if m.available, err = readOptionalKmodList(filepath.Join(dir, "modules.dep"), ":"); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// modulesDir returns the directory of the modules of the given kernel version
+func modulesDir(kVer string) string {
+	dir := source.LibDir.Path("modules", kVer)
+	if _, err := source.Stat(dir); err == nil {
+		return dir
+	}
+	return source.UsrLibDir.Path("modules", kVer)
+}
+
+// Status returns the state of a kernel module, one of KmodLoaded,
+// KmodBuiltin, KmodAvailable or KmodMissing
+func (m *KernelModules) Status(name string) string {
+	name = KmodName(name)
+	if _, ok := m.loaded[name]; ok {
+		return KmodLoaded
+	}
+	if _, ok := m.builtin[name]; ok {
+		return KmodBuiltin
+	}
+	if _, ok := m.available[name]; ok {
+		return KmodAvailable
+	}
+	return KmodMissing
+}
+
+// readOptionalKmodList reads a module list with readKmodList, returning an
+// empty list if the file does not exist
+func readOptionalKmodList(filename, sep string) (map[string]struct{}, error) {
+	modules, err := readKmodList(filename, sep)
+	if os.IsNotExist(err) {
+		return map[string]struct{}{}, nil
+	}
+	return modules, err
+}
+
+// readKmodList reads the module names from a file with one module per line.
+// Anything after the separator is ignored. Module paths, such as
+// "kernel/drivers/vfio/pci/vfio-pci.ko.xz", are converted to module names.
+func readKmodList(filename, sep string) (map[string]struct{}, error) {
+	f, err := source.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	modules := map[string]struct{}{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if sep != "" {
+			line = strings.SplitN(line, sep, 2)[0]
+		}
+		if line == "" {
+			continue
+		}
+		name := path.Base(line)
+		if i := strings.Index(name, ".ko"); i >= 0 {
+			name = name[:i]
+		}
+		modules[KmodName(name)] = struct{}{}
+	}
+	return modules, scanner.Err()
+}
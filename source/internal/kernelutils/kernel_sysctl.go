@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kernelutils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/node-feature-discovery/source"
+)
+
+var sysctlComponentRe = regexp.MustCompile(`^[A-Za-z0-9_.\-]+$`)
+
+// sysctlComponents splits a sysctl key into the components of its path under
+// /proc/sys. Like with the sysctl tool, the key may be separated either with
+// dots, e.g. "vm.nr_hugepages", or with slashes, which allows components
+// containing dots, e.g. "net/ipv4/conf/eth0.100/rp_filter".
+func sysctlComponents(key string) ([]string, error) {
+	sep := "."
+	if strings.Contains(key, "/") {
+		sep = "/"
+	}
+	components := strings.Split(key, sep)
+	for _, c := range components {
+		if !sysctlComponentRe.MatchString(c) || c == "." || c == ".." {
+			return nil, fmt.Errorf("invalid sysctl key %q", key)
+		}
+	}
+	return components, nil
+}
+
+// ValidateSysctlKey returns an error if the sysctl key is malformed
+func ValidateSysctlKey(key string) error {
+	_, err := sysctlComponents(key)
+	return err
+}
+
+// SysctlName returns the canonical, dot-separated name of a sysctl key
+func SysctlName(key string) string {
+	return strings.Replace(key, "/", ".", -1)
+}
+
+// ReadSysctl reads the value of a kernel parameter from /proc/sys. Runs of
+// whitespace, separating the fields of multi-valued parameters, are
+// collapsed into single spaces.
+func ReadSysctl(key string) (string, error) {
+	components, err := sysctlComponents(key)
+	if err != nil {
+		return "", err
+	}
+	data, err := source.ReadFile(source.ProcfsDir.Path("sys/" + strings.Join(components, "/")))
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(strings.Fields(string(data)), " "), nil
+}
@@ -17,12 +17,13 @@ limitations under the License.
 package kernelutils
 
 import (
-	"io/ioutil"
 	"strings"
+
+	"sigs.k8s.io/node-feature-discovery/source"
 )
 
 func GetKernelVersion() (string, error) {
-	unameRaw, err := ioutil.ReadFile("/proc/sys/kernel/osrelease")
+	unameRaw, err := source.ReadFile(source.ProcfsDir.Path("sys/kernel/osrelease"))
 	if err != nil {
 		return "", err
 	}
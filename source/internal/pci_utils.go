@@ -18,11 +18,10 @@ package busutils
 
 import (
 	"fmt"
-	"io/ioutil"
-	"log"
 	"path"
 	"strings"
 
+	"sigs.k8s.io/node-feature-discovery/pkg/logger"
 	"sigs.k8s.io/node-feature-discovery/source"
 )
 
@@ -34,7 +33,7 @@ var ExtraPciDevAttrs = []string{"sriov_totalvfs"}
 // Read a single PCI device attribute
 // A PCI attribute in this context, maps to the corresponding sysfs file
 func readSinglePciAttribute(devPath string, attrName string) (string, error) {
-	data, err := ioutil.ReadFile(path.Join(devPath, attrName))
+	data, err := source.ReadFile(path.Join(devPath, attrName))
 	if err != nil {
 		return "", fmt.Errorf("failed to read device attribute %s: %v", attrName, err)
 	}
@@ -77,7 +76,7 @@ func DetectPci(deviceAttrSpec map[string]bool) (map[string][]PciDeviceInfo, erro
 	sysfsBasePath := source.SysfsDir.Path("bus/pci/devices")
 	devInfo := make(map[string][]PciDeviceInfo)
 
-	devices, err := ioutil.ReadDir(sysfsBasePath)
+	devices, err := source.ReadDir(sysfsBasePath)
 	if err != nil {
 		return nil, err
 	}
@@ -88,7 +87,7 @@ func DetectPci(deviceAttrSpec map[string]bool) (map[string][]PciDeviceInfo, erro
 	for _, device := range devices {
 		info, err := readPciDevInfo(path.Join(sysfsBasePath, device.Name()), deviceAttrSpec)
 		if err != nil {
-			log.Print(err)
+			logger.Error(err, "failed to read PCI device info", "device", device.Name())
 			continue
 		}
 		class := info["class"]
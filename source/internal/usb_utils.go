@@ -18,11 +18,12 @@ package busutils
 
 import (
 	"fmt"
-	"io/ioutil"
-	"log"
 	"path"
 	"path/filepath"
 	"strings"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/logger"
+	"sigs.k8s.io/node-feature-discovery/source"
 )
 
 type UsbDeviceInfo map[string]string
@@ -39,7 +40,7 @@ var devAttrFileMap = map[string]string{
 }
 
 func readSingleUsbSysfsAttribute(path string) (string, error) {
-	data, err := ioutil.ReadFile(path)
+	data, err := source.ReadFile(path)
 	if err != nil {
 		return "", fmt.Errorf("failed to read device attribute %s: %v", filepath.Base(path), err)
 	}
@@ -73,7 +74,7 @@ func readUsbDevInfo(devPath string, deviceAttrSpec map[string]bool) (UsbClassMap
 		classmap[info["class"]] = info
 	} else {
 		// Otherwise, if a 00 is presented at the device level, descend to the interface level.
-		interfaces, err := filepath.Glob(devPath + "/*/bInterfaceClass")
+		interfaces, err := source.Glob(devPath + "/*/bInterfaceClass")
 		if err != nil {
 			return classmap, err
 		}
@@ -110,10 +111,10 @@ func DetectUsb(deviceAttrSpec map[string]bool) (map[string][]UsbDeviceInfo, erro
 	// Unlike PCI, the USB sysfs interface includes entries not just for
 	// devices. We work around this by globbing anything that includes a
 	// valid product ID.
-	const devicePath = "/sys/bus/usb/devices/*/idProduct"
+	devicePath := source.SysfsDir.Path("bus/usb/devices/*/idProduct")
 	devInfo := make(map[string][]UsbDeviceInfo)
 
-	devices, err := filepath.Glob(devicePath)
+	devices, err := source.Glob(devicePath)
 	if err != nil {
 		return nil, err
 	}
@@ -125,7 +126,7 @@ func DetectUsb(deviceAttrSpec map[string]bool) (map[string][]UsbDeviceInfo, erro
 	for _, device := range devices {
 		devMap, err := readUsbDevInfo(filepath.Dir(device), deviceAttrSpec)
 		if err != nil {
-			log.Print(err)
+			logger.Error(err, "failed to read USB device info", "device", device)
 			continue
 		}
 
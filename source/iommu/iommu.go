@@ -18,30 +18,46 @@ package iommu
 
 import (
 	"fmt"
-	"io/ioutil"
 
+	"sigs.k8s.io/node-feature-discovery/pkg/logger"
 	"sigs.k8s.io/node-feature-discovery/source"
 )
 
+var log = logger.WithValues("source", "iommu")
+
+// Configuration file options
+type Config struct {
+	source.LabelFilterConfig
+}
+
 // Implement FeatureSource interface
-type Source struct{}
+type Source struct {
+	config *Config
+}
 
 func (s Source) Name() string { return "iommu" }
 
 // NewConfig method of the FeatureSource interface
-func (s *Source) NewConfig() source.Config { return nil }
+func (s *Source) NewConfig() source.Config { return &Config{} }
 
 // GetConfig method of the FeatureSource interface
-func (s *Source) GetConfig() source.Config { return nil }
+func (s *Source) GetConfig() source.Config { return s.config }
 
 // SetConfig method of the FeatureSource interface
-func (s *Source) SetConfig(source.Config) {}
+func (s *Source) SetConfig(conf source.Config) {
+	switch v := conf.(type) {
+	case *Config:
+		s.config = v
+	default:
+		log.Error(fmt.Errorf("invalid config type: %T", conf), "failed to configure source")
+	}
+}
 
 func (s Source) Discover() (source.Features, error) {
 	features := source.Features{}
 
 	// Check if any iommu devices are available
-	devices, err := ioutil.ReadDir(source.SysfsDir.Path("class/iommu/"))
+	devices, err := source.ReadDir(source.SysfsDir.Path("class/iommu/"))
 	if err != nil {
 		return nil, fmt.Errorf("Failed to check for IOMMU support: %v", err)
 	}
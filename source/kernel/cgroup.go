@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kernel
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/node-feature-discovery/source"
+)
+
+// cgroupInfo describes the cgroup setup of the host
+type cgroupInfo struct {
+	// Version is "v1", "v2" or "hybrid", i.e. v1 controllers with the v2
+	// hierarchy mounted at /sys/fs/cgroup/unified
+	Version     string
+	Controllers []string
+}
+
+// discoverCgroup detects the cgroup version and the available controllers.
+// Nil is returned if cgroups are not in use.
+func discoverCgroup() (*cgroupInfo, error) {
+	// The cgroup v2 hierarchy mounted at the root lists its controllers
+	data, err := source.ReadFile(source.SysfsDir.Path("fs/cgroup/cgroup.controllers"))
+	if err == nil {
+		return &cgroupInfo{Version: "v2", Controllers: strings.Fields(string(data))}, nil
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	// Controllers bound to a v1 hierarchy have a non-zero hierarchy ID
+	data, err = source.ReadFile(source.ProcfsDir.Path("cgroups"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	controllers := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		// Format: subsys_name hierarchy num_cgroups enabled
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if fields[1] != "0" && fields[3] == "1" {
+			controllers = append(controllers, fields[0])
+		}
+	}
+	if len(controllers) == 0 {
+		return nil, nil
+	}
+
+	info := &cgroupInfo{Version: "v1", Controllers: controllers}
+	if _, err := source.Stat(source.SysfsDir.Path("fs/cgroup/unified/cgroup.controllers")); err == nil {
+		info.Version = "hybrid"
+	}
+	return info, nil
+}
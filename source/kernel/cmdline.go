@@ -0,0 +1,226 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kernel
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/node-feature-discovery/source"
+)
+
+// Characters not allowed in label values
+var labelValueForbidden = regexp.MustCompile("[^-A-Za-z0-9_.]")
+
+// maxCPUs is the highest number of CPUs supported by the kernel, limiting
+// the CPU numbers accepted in CPU lists
+const maxCPUs = 8192
+
+// parseCmdline reads the kernel command line. Parameters without a value are
+// reported as "true". Dashes in parameter names are converted to
+// underscores, as the kernel treats them the same. If a parameter is given
+// multiple times the last value is reported. Parameters after "--" are passed
+// to init and are not kernel parameters.
+func parseCmdline() (map[string]string, error) {
+	data, err := source.ReadFile(source.ProcfsDir.Path("cmdline"))
+	if err != nil {
+		return nil, err
+	}
+
+	params := map[string]string{}
+	for _, field := range splitCmdline(string(data)) {
+		if field == "--" {
+			break
+		}
+		split := strings.SplitN(field, "=", 2)
+		name := strings.Replace(split[0], "-", "_", -1)
+		if len(split) == 1 {
+			params[name] = "true"
+		} else {
+			params[name] = split[1]
+		}
+	}
+	return params, nil
+}
+
+// splitCmdline splits the kernel command line into parameters, taking
+// double-quoted values containing spaces into account
+func splitCmdline(cmdline string) []string {
+	fields := []string{}
+	quoted := false
+	field := strings.Builder{}
+	for _, c := range strings.TrimSpace(cmdline) {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case !quoted && (c == ' ' || c == '\t' || c == '\n'):
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+				field.Reset()
+			}
+		default:
+			field.WriteRune(c)
+		}
+	}
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
+
+// normalizeCmdlineValue turns a parameter value into a label value by
+// replacing forbidden characters, e.g. the commas of CPU lists, with
+// underscores
+func normalizeCmdlineValue(value string) string {
+	if value == "" {
+		return "true"
+	}
+	return labelValueForbidden.ReplaceAllString(value, "_")
+}
+
+// isolatedCPUs returns the CPUs isolated with the isolcpus parameter as a
+// normalized CPU list, e.g. "2-5_8". Isolation flags, such as domain and
+// managed_irq, are ignored.
+func isolatedCPUs(isolcpus string) (string, error) {
+	cpus := []string{}
+	for _, item := range strings.Split(isolcpus, ",") {
+		switch item {
+		case "nohz", "domain", "managed_irq":
+			continue
+		}
+		cpus = append(cpus, item)
+	}
+
+	list, err := parseCPUList(strings.Join(cpus, ","))
+	if err != nil {
+		return "", fmt.Errorf("invalid isolcpus %q: %v", isolcpus, err)
+	}
+	value := formatCPUList(list)
+	if msgs := validation.IsValidLabelValue(value); len(msgs) > 0 {
+		return "", fmt.Errorf("isolated CPUs %q are not a valid label value: %s", value, strings.Join(msgs, "; "))
+	}
+	return value, nil
+}
+
+// parseCPUList parses a CPU list, e.g. "0-3,8", into sorted CPU numbers. Ranges
+// may use the group syntax of the kernel, e.g. "0-15:2/4" for the first two
+// CPUs of every group of four.
+func parseCPUList(s string) ([]int, error) {
+	set := map[int]struct{}{}
+	for _, item := range strings.Split(s, ",") {
+		if item == "" {
+			continue
+		}
+		first, last, used, groupSize, err := parseCPURange(item)
+		if err != nil {
+			return nil, err
+		}
+		for group := first; group <= last; group += groupSize {
+			for i := group; i < group+used && i <= last; i++ {
+				set[i] = struct{}{}
+			}
+		}
+	}
+
+	cpus := make([]int, 0, len(set))
+	for i := range set {
+		cpus = append(cpus, i)
+	}
+	sort.Ints(cpus)
+	return cpus, nil
+}
+
+// parseCPURange parses one item of a CPU list, i.e. a single CPU, a range of
+// CPUs "a-b", or a range with groups "a-b:used/group_size"
+func parseCPURange(item string) (first, last, used, groupSize int, err error) {
+	cpus := item
+	groups := ""
+	if i := strings.Index(item, ":"); i >= 0 {
+		cpus, groups = item[:i], item[i+1:]
+	}
+
+	bounds := strings.SplitN(cpus, "-", 2)
+	if first, err = parseCPU(bounds[0]); err != nil {
+		return
+	}
+	last = first
+	if len(bounds) == 2 {
+		if last, err = parseCPU(bounds[1]); err != nil {
+			return
+		}
+	}
+	if last < first {
+		err = fmt.Errorf("invalid CPU range %q", item)
+		return
+	}
+
+	used, groupSize = 1, 1
+	if groups != "" {
+		split := strings.SplitN(groups, "/", 2)
+		if len(split) != 2 {
+			err = fmt.Errorf("invalid CPU groups in %q", item)
+			return
+		}
+		if used, err = strconv.Atoi(split[0]); err != nil {
+			return
+		}
+		if groupSize, err = strconv.Atoi(split[1]); err != nil {
+			return
+		}
+		if used <= 0 || groupSize <= 0 || used > groupSize {
+			err = fmt.Errorf("invalid CPU groups in %q", item)
+			return
+		}
+	}
+	return
+}
+
+// parseCPU parses one CPU number, not accepting numbers beyond the number of
+// CPUs supported by the kernel
+func parseCPU(s string) (int, error) {
+	cpu, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	if cpu < 0 || cpu >= maxCPUs {
+		return 0, fmt.Errorf("CPU number %d out of range", cpu)
+	}
+	return cpu, nil
+}
+
+// formatCPUList formats sorted CPU numbers as a list of ranges separated by
+// underscores, commas not being allowed in label values
+func formatCPUList(cpus []int) string {
+	ranges := []string{}
+	for i := 0; i < len(cpus); {
+		j := i
+		for j+1 < len(cpus) && cpus[j+1] == cpus[j]+1 {
+			j++
+		}
+		if i == j {
+			ranges = append(ranges, strconv.Itoa(cpus[i]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", cpus[i], cpus[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(ranges, "_")
+}
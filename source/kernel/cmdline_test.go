@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kernel
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSplitCmdline(t *testing.T) {
+	Convey("When splitting the kernel command line", t, func() {
+		for _, tc := range []struct {
+			cmdline  string
+			expected []string
+		}{
+			{"", []string{}},
+			{"ro quiet\n", []string{"ro", "quiet"}},
+			{"a=1  b=2\tc", []string{"a=1", "b=2", "c"}},
+			{`dyndbg="file foo.c +p" ro`, []string{"dyndbg=file foo.c +p", "ro"}},
+			{"ro -- single", []string{"ro", "--", "single"}},
+		} {
+			So(splitCmdline(tc.cmdline), ShouldResemble, tc.expected)
+		}
+	})
+}
+
+func TestParseCPUList(t *testing.T) {
+	Convey("When parsing CPU lists", t, func() {
+		Convey("valid lists are sorted and deduplicated", func() {
+			for _, tc := range []struct {
+				list     string
+				expected []int
+			}{
+				{"", []int{}},
+				{"3", []int{3}},
+				{"8,0-3", []int{0, 1, 2, 3, 8}},
+				{"0-3,2-5", []int{0, 1, 2, 3, 4, 5}},
+				{"0-15:2/4", []int{0, 1, 4, 5, 8, 9, 12, 13}},
+				{"0-6:2/4", []int{0, 1, 4, 5}},
+				{"2-9:1/3", []int{2, 5, 8}},
+				{"0-3:4/4", []int{0, 1, 2, 3}},
+				{"8191", []int{8191}},
+			} {
+				cpus, err := parseCPUList(tc.list)
+				So(err, ShouldBeNil)
+				So(cpus, ShouldResemble, tc.expected)
+			}
+		})
+
+		Convey("invalid lists are rejected", func() {
+			for _, list := range []string{
+				"a",
+				"3-1",
+				"0-",
+				"-1",
+				"8192",
+				"0-100000000",
+				"0-15:2",
+				"0-15:0/4",
+				"0-15:5/4",
+				"0-15:x/4",
+			} {
+				_, err := parseCPUList(list)
+				So(err, ShouldNotBeNil)
+			}
+		})
+	})
+}
+
+func TestIsolatedCPUs(t *testing.T) {
+	Convey("When getting the isolated CPUs", t, func() {
+		for _, tc := range []struct {
+			isolcpus string
+			expected string
+			valid    bool
+		}{
+			{"1-3", "1-3", true},
+			{"8,2-5", "2-5_8", true},
+			{"domain,managed_irq,1,3,2", "1-3", true},
+			{"nohz,0-7:1/2", "0_2_4_6", true},
+			{"domain", "", true},
+			{"foo,1", "", false},
+			// Does not fit in a label value
+			{"0-100:1/2", "", false},
+		} {
+			cpus, err := isolatedCPUs(tc.isolcpus)
+			if tc.valid {
+				So(err, ShouldBeNil)
+			} else {
+				So(err, ShouldNotBeNil)
+			}
+			So(cpus, ShouldEqual, tc.expected)
+		}
+	})
+}
@@ -17,17 +17,35 @@ limitations under the License.
 package kernel
 
 import (
-	"log"
+	"fmt"
 	"regexp"
+	"sort"
+	"strings"
 
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/node-feature-discovery/pkg/logger"
 	"sigs.k8s.io/node-feature-discovery/source"
 	"sigs.k8s.io/node-feature-discovery/source/internal/kernelutils"
 )
 
+var log = logger.WithValues("source", "kernel")
+
 // Configuration file options
 type Config struct {
-	KconfigFile string
-	ConfigOpts  []string `json:"configOpts,omitempty"`
+	KconfigFile string      `json:"kconfigFile,omitempty"`
+	ConfigOpts  []string    `json:"configOpts,omitempty"`
+	CmdlineOpts []string    `json:"cmdlineOpts,omitempty"`
+	SysctlOpts  []sysctlOpt `json:"sysctlOpts,omitempty"`
+	ModuleOpts  []string    `json:"moduleOpts,omitempty"`
+
+	source.LabelFilterConfig
+}
+
+// sysctlOpt is a kernel parameter to publish. Values maps the values of the
+// parameter to label values, values not listed are published as they are.
+type sysctlOpt struct {
+	Key    string            `json:"key"`
+	Values map[string]string `json:"values,omitempty"`
 }
 
 // newDefaultConfig returns a new config with pre-populated defaults
@@ -43,6 +61,32 @@ func newDefaultConfig() *Config {
 	}
 }
 
+// Validate method of the ConfigValidator interface
+func (c *Config) Validate() []error {
+	errs := []error{}
+	for _, name := range c.ModuleOpts {
+		if err := kernelutils.ValidateKmodName(name); err != nil {
+			errs = append(errs, fmt.Errorf("moduleOpts: %v", err))
+		}
+	}
+	for i, opt := range c.SysctlOpts {
+		if err := kernelutils.ValidateSysctlKey(opt.Key); err != nil {
+			errs = append(errs, fmt.Errorf("sysctlOpts[%d]: %v", i, err))
+		}
+		values := make([]string, 0, len(opt.Values))
+		for v := range opt.Values {
+			values = append(values, v)
+		}
+		sort.Strings(values)
+		for _, v := range values {
+			if msgs := validation.IsValidLabelValue(opt.Values[v]); len(msgs) > 0 {
+				errs = append(errs, fmt.Errorf("sysctlOpts[%d]: invalid label value %q for value %q: %s", i, opt.Values[v], v, strings.Join(msgs, "; ")))
+			}
+		}
+	}
+	return errs
+}
+
 // Implement FeatureSource interface
 type Source struct {
 	config *Config
@@ -62,7 +106,7 @@ func (s *Source) SetConfig(conf source.Config) {
 	case *Config:
 		s.config = v
 	default:
-		log.Printf("PANIC: invalid config type: %T", conf)
+		log.Error(fmt.Errorf("invalid config type: %T", conf), "failed to configure source")
 	}
 }
 
@@ -72,7 +116,7 @@ func (s *Source) Discover() (source.Features, error) {
 	// Read kernel version
 	version, err := parseVersion()
 	if err != nil {
-		log.Printf("ERROR: Failed to get kernel version: %s", err)
+		log.Error(err, "failed to get kernel version")
 	} else {
 		for key := range version {
 			features["version."+key] = version[key]
@@ -82,7 +126,7 @@ func (s *Source) Discover() (source.Features, error) {
 	// Read kconfig
 	kconfig, err := kernelutils.ParseKconfig(s.config.KconfigFile)
 	if err != nil {
-		log.Printf("ERROR: Failed to read kconfig: %s", err)
+		log.Error(err, "failed to read kconfig")
 	}
 
 	// Check flags
@@ -92,13 +136,88 @@ func (s *Source) Discover() (source.Features, error) {
 		}
 	}
 
+	// Check kernel command line parameters
+	cmdline, err := parseCmdline()
+	if err != nil {
+		log.Error(err, "failed to read kernel command line")
+	} else {
+		for _, opt := range s.config.CmdlineOpts {
+			name := strings.Replace(opt, "-", "_", -1)
+			val, ok := cmdline[name]
+			if !ok {
+				continue
+			}
+			features["cmdline."+name] = normalizeCmdlineValue(val)
+
+			// The isolated CPUs are derived from isolcpus
+			if name == "isolcpus" {
+				cpus, err := isolatedCPUs(val)
+				if err != nil {
+					log.Error(err, "failed to parse isolated CPUs")
+				} else if cpus != "" {
+					features["cmdline.isolated_cpus"] = cpus
+				}
+			}
+		}
+	}
+
+	// Check kernel parameters
+	for name, val := range discoverSysctls(s.config.SysctlOpts) {
+		features["sysctl."+name] = val
+	}
+
+	// Check kernel modules
+	if len(s.config.ModuleOpts) > 0 {
+		modules, err := kernelutils.GetKernelModules()
+		if err != nil {
+			log.Error(err, "failed to read kernel modules")
+		} else {
+			for _, name := range s.config.ModuleOpts {
+				features["module."+kernelutils.KmodName(name)] = modules.Status(name)
+			}
+		}
+	}
+
 	selinux, err := SelinuxEnabled()
 	if err != nil {
-		log.Print(err)
+		log.Error(err, "failed to detect selinux")
 	} else if selinux {
 		features["selinux.enabled"] = true
 	}
 
+	mode, err := selinuxMode()
+	if err != nil {
+		log.Error(err, "failed to detect selinux mode")
+	} else if mode != "" {
+		features["selinux.mode"] = mode
+	}
+
+	// Detect Linux security modules
+	lsms, err := discoverLSMs()
+	if err != nil {
+		log.Error(err, "failed to detect Linux security modules")
+	}
+	for _, lsm := range lsms {
+		features["lsm."+lsm+".enabled"] = true
+	}
+	lockdown, err := lockdownMode()
+	if err != nil {
+		log.Error(err, "failed to detect kernel lockdown mode")
+	} else if lockdown != "" {
+		features["lsm.lockdown.mode"] = lockdown
+	}
+
+	// Detect cgroups
+	cgroup, err := discoverCgroup()
+	if err != nil {
+		log.Error(err, "failed to detect cgroups")
+	} else if cgroup != nil {
+		features["cgroup.version"] = cgroup.Version
+		for _, c := range cgroup.Controllers {
+			features["cgroup.controller."+c] = true
+		}
+	}
+
 	return features, nil
 }
 
@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kernel
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/node-feature-discovery/source"
+)
+
+// discoverLSMs returns the names of the active Linux security modules, in
+// the order they are stacked. Nil is returned if securityfs is not mounted.
+func discoverLSMs() ([]string, error) {
+	data, err := source.ReadFile(source.SysfsDir.Path("kernel/security/lsm"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	lsms := []string{}
+	for _, lsm := range strings.Split(strings.TrimSpace(string(data)), ",") {
+		if lsm != "" {
+			lsms = append(lsms, lsm)
+		}
+	}
+	return lsms, nil
+}
+
+// Regexp for the selected lockdown mode, e.g. "none [integrity] confidentiality"
+var lockdownModeRe = regexp.MustCompile(`\[([a-z]+)\]`)
+
+// lockdownMode returns the kernel lockdown mode, empty if the lockdown LSM
+// is not active
+func lockdownMode() (string, error) {
+	data, err := source.ReadFile(source.SysfsDir.Path("kernel/security/lockdown"))
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	m := lockdownModeRe.FindStringSubmatch(string(data))
+	if m == nil {
+		return "", fmt.Errorf("failed to parse lockdown mode %q", strings.TrimSpace(string(data)))
+	}
+	return m[1], nil
+}
+
+// selinuxMode returns the SELinux mode, "enforcing" or "permissive", empty
+// if SELinux is disabled
+func selinuxMode() (string, error) {
+	data, err := source.ReadFile(source.SysfsDir.Path("fs/selinux/enforce"))
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(string(data)) == "1" {
+		return "enforcing", nil
+	}
+	return "permissive", nil
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kernel
+
+import (
+	"os"
+
+	"sigs.k8s.io/node-feature-discovery/source/internal/kernelutils"
+)
+
+// discoverSysctls reads the configured kernel parameters, keyed by their
+// dot-separated name. Parameters that do not exist are skipped.
+func discoverSysctls(opts []sysctlOpt) map[string]string {
+	sysctls := map[string]string{}
+	for _, opt := range opts {
+		val, err := kernelutils.ReadSysctl(opt.Key)
+		if os.IsNotExist(err) {
+			log.V(1).Info("sysctl not found", "key", opt.Key)
+			continue
+		} else if err != nil {
+			log.Error(err, "failed to read sysctl", "key", opt.Key)
+			continue
+		}
+		if mapped, ok := opt.Values[val]; ok {
+			val = mapped
+		} else {
+			// Fields of multi-valued parameters are separated with spaces
+			val = labelValueForbidden.ReplaceAllString(val, "_")
+		}
+		sysctls[kernelutils.SysctlName(opt.Key)] = val
+	}
+	return sysctls
+}
@@ -20,51 +20,72 @@ import (
 	"bytes"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 
+	"sigs.k8s.io/node-feature-discovery/pkg/logger"
 	"sigs.k8s.io/node-feature-discovery/source"
 )
 
-// Config
-var (
-	featureFilesDir = "/etc/kubernetes/node-feature-discovery/features.d/"
-	hookDir         = "/etc/kubernetes/node-feature-discovery/source.d/"
-)
+var log = logger.WithValues("source", "local")
+
+// Config holds the directories of the local source. These are directories of
+// nfd-worker itself, not of the host system to be inspected.
+type Config struct {
+	FeatureFilesDir string `json:"featureFilesDir,omitempty"`
+	HooksDir        string `json:"hooksDir,omitempty"`
+
+	source.LabelFilterConfig
+}
+
+// newDefaultConfig returns a new config with pre-populated defaults
+func newDefaultConfig() *Config {
+	return &Config{
+		FeatureFilesDir: "/etc/kubernetes/node-feature-discovery/features.d/",
+		HooksDir:        "/etc/kubernetes/node-feature-discovery/source.d/",
+	}
+}
 
 // Implement FeatureSource interface
-type Source struct{}
+type Source struct {
+	config *Config
+}
 
 func (s Source) Name() string { return "local" }
 
 // NewConfig method of the FeatureSource interface
-func (s *Source) NewConfig() source.Config { return nil }
+func (s *Source) NewConfig() source.Config { return newDefaultConfig() }
 
 // GetConfig method of the FeatureSource interface
-func (s *Source) GetConfig() source.Config { return nil }
+func (s *Source) GetConfig() source.Config { return s.config }
 
 // SetConfig method of the FeatureSource interface
-func (s *Source) SetConfig(source.Config) {}
+func (s *Source) SetConfig(conf source.Config) {
+	switch v := conf.(type) {
+	case *Config:
+		s.config = v
+	default:
+		log.Error(fmt.Errorf("invalid config type: %T", conf), "failed to configure source")
+	}
+}
 
 func (s Source) Discover() (source.Features, error) {
-	featuresFromHooks, err := getFeaturesFromHooks()
+	featuresFromHooks, err := getFeaturesFromHooks(s.config.HooksDir)
 	if err != nil {
-		log.Printf("%v", err)
+		log.Error(err, "failed to get features from hooks")
 	}
 
-	featuresFromFiles, err := getFeaturesFromFiles()
+	featuresFromFiles, err := getFeaturesFromFiles(s.config.FeatureFilesDir)
 	if err != nil {
-		log.Printf("%v", err)
+		log.Error(err, "failed to get features from feature files")
 	}
 
 	// Merge features from hooks and files
 	for k, v := range featuresFromHooks {
 		if old, ok := featuresFromFiles[k]; ok {
-			log.Printf("WARNING: overriding label '%s': value changed from '%s' to '%s'",
-				k, old, v)
+			log.Warning("overriding label", "feature", k, "oldValue", old, "newValue", v)
 		}
 		featuresFromFiles[k] = v
 	}
@@ -104,13 +125,13 @@ func parseFeatures(lines [][]byte, prefix string) source.Features {
 }
 
 // Run all hooks and get features
-func getFeaturesFromHooks() (source.Features, error) {
+func getFeaturesFromHooks(hookDir string) (source.Features, error) {
 	features := source.Features{}
 
 	files, err := ioutil.ReadDir(hookDir)
 	if err != nil {
 		if os.IsNotExist(err) {
-			log.Printf("ERROR: hook directory %v does not exist", hookDir)
+			log.Warning("hook directory does not exist", "path", hookDir)
 			return features, nil
 		}
 		return features, fmt.Errorf("Unable to access %v: %v", hookDir, err)
@@ -118,17 +139,16 @@ func getFeaturesFromHooks() (source.Features, error) {
 
 	for _, file := range files {
 		fileName := file.Name()
-		lines, err := runHook(fileName)
+		lines, err := runHook(hookDir, fileName)
 		if err != nil {
-			log.Printf("ERROR: source local failed running hook '%v': %v", fileName, err)
+			log.Error(err, "failed running hook", "hook", fileName)
 			continue
 		}
 
 		// Append features
 		for k, v := range parseFeatures(lines, fileName) {
 			if old, ok := features[k]; ok {
-				log.Printf("WARNING: overriding label '%s' from another hook (%s): value changed from '%s' to '%s'",
-					k, fileName, old, v)
+				log.Warning("overriding label from another hook", "feature", k, "hook", fileName, "oldValue", old, "newValue", v)
 			}
 			features[k] = v
 		}
@@ -138,13 +158,13 @@ func getFeaturesFromHooks() (source.Features, error) {
 }
 
 // Run one hook
-func runHook(file string) ([][]byte, error) {
+func runHook(hookDir string, file string) ([][]byte, error) {
 	var lines [][]byte
 
 	path := filepath.Join(hookDir, file)
 	filestat, err := os.Stat(path)
 	if err != nil {
-		log.Printf("ERROR: skipping %v, failed to get stat: %v", path, err)
+		log.Error(err, "skipping hook, failed to get stat", "path", path)
 		return lines, err
 	}
 
@@ -165,7 +185,7 @@ func runHook(file string) ([][]byte, error) {
 				// Don't print the last empty string
 				break
 			}
-			log.Printf("%v: %s", file, line)
+			log.Info("hook output on stderr", "hook", file, "line", string(line))
 		}
 
 		// Do not return any lines if an error occurred
@@ -179,13 +199,13 @@ func runHook(file string) ([][]byte, error) {
 }
 
 // Read all files to get features
-func getFeaturesFromFiles() (source.Features, error) {
+func getFeaturesFromFiles(featureFilesDir string) (source.Features, error) {
 	features := source.Features{}
 
 	files, err := ioutil.ReadDir(featureFilesDir)
 	if err != nil {
 		if os.IsNotExist(err) {
-			log.Printf("ERROR: features directory %v does not exist", featureFilesDir)
+			log.Warning("features directory does not exist", "path", featureFilesDir)
 			return features, nil
 		}
 		return features, fmt.Errorf("Unable to access %v: %v", featureFilesDir, err)
@@ -193,17 +213,16 @@ func getFeaturesFromFiles() (source.Features, error) {
 
 	for _, file := range files {
 		fileName := file.Name()
-		lines, err := getFileContent(fileName)
+		lines, err := getFileContent(featureFilesDir, fileName)
 		if err != nil {
-			log.Printf("ERROR: source local failed reading file '%v': %v", fileName, err)
+			log.Error(err, "failed reading feature file", "file", fileName)
 			continue
 		}
 
 		// Append features
 		for k, v := range parseFeatures(lines, fileName) {
 			if old, ok := features[k]; ok {
-				log.Printf("WARNING: overriding label '%s' from another features.d file (%s): value changed from '%s' to '%s'",
-					k, fileName, old, v)
+				log.Warning("overriding label from another features.d file", "feature", k, "file", fileName, "oldValue", old, "newValue", v)
 			}
 			features[k] = v
 		}
@@ -213,13 +232,13 @@ func getFeaturesFromFiles() (source.Features, error) {
 }
 
 // Read one file
-func getFileContent(fileName string) ([][]byte, error) {
+func getFileContent(featureFilesDir string, fileName string) ([][]byte, error) {
 	var lines [][]byte
 
 	path := filepath.Join(featureFilesDir, fileName)
 	filestat, err := os.Stat(path)
 	if err != nil {
-		log.Printf("ERROR: skipping %v, failed to get stat: %v", path, err)
+		log.Error(err, "skipping feature file, failed to get stat", "path", path)
 		return lines, err
 	}
 
@@ -17,28 +17,44 @@ limitations under the License.
 package memory
 
 import (
-	"io/ioutil"
-	"log"
+	"fmt"
 	"os"
 	"strings"
 
+	"sigs.k8s.io/node-feature-discovery/pkg/logger"
 	"sigs.k8s.io/node-feature-discovery/source"
 )
 
+var log = logger.WithValues("source", "memory")
+
+// Configuration file options
+type Config struct {
+	source.LabelFilterConfig
+}
+
 // Source implements FeatureSource.
-type Source struct{}
+type Source struct {
+	config *Config
+}
 
 // Name returns an identifier string for this feature source.
 func (s Source) Name() string { return "memory" }
 
 // NewConfig method of the FeatureSource interface
-func (s *Source) NewConfig() source.Config { return nil }
+func (s *Source) NewConfig() source.Config { return &Config{} }
 
 // GetConfig method of the FeatureSource interface
-func (s *Source) GetConfig() source.Config { return nil }
+func (s *Source) GetConfig() source.Config { return s.config }
 
 // SetConfig method of the FeatureSource interface
-func (s *Source) SetConfig(source.Config) {}
+func (s *Source) SetConfig(conf source.Config) {
+	switch v := conf.(type) {
+	case *Config:
+		s.config = v
+	default:
+		log.Error(fmt.Errorf("invalid config type: %T", conf), "failed to configure source")
+	}
+}
 
 // Discover returns feature names for memory: numa if more than one memory node is present.
 func (s Source) Discover() (source.Features, error) {
@@ -47,7 +63,7 @@ func (s Source) Discover() (source.Features, error) {
 	// Detect NUMA
 	numa, err := isNuma()
 	if err != nil {
-		log.Printf("ERROR: failed to detect NUMA topology: %s", err)
+		log.Error(err, "failed to detect NUMA topology")
 	} else if numa {
 		features["numa"] = true
 	}
@@ -55,7 +71,7 @@ func (s Source) Discover() (source.Features, error) {
 	// Detect NVDIMM
 	nv, err := detectNvdimm()
 	if err != nil {
-		log.Printf("ERROR: NVDIMM detection failed: %s", err)
+		log.Error(err, "NVDIMM detection failed")
 	} else {
 		for k, v := range nv {
 			features["nv."+k] = v
@@ -69,7 +85,7 @@ func (s Source) Discover() (source.Features, error) {
 func isNuma() (bool, error) {
 	// Find out how many nodes are online
 	// Multiple nodes is a sign of NUMA
-	bytes, err := ioutil.ReadFile(source.SysfsDir.Path("devices/system/node/online"))
+	bytes, err := source.ReadFile(source.SysfsDir.Path("devices/system/node/online"))
 	if err != nil {
 		return false, err
 	}
@@ -90,7 +106,7 @@ func detectNvdimm() (map[string]bool, error) {
 	features := make(map[string]bool)
 
 	// Check presence of physical devices
-	devices, err := ioutil.ReadDir(source.SysfsDir.Path("class/nd"))
+	devices, err := source.ReadDir(source.SysfsDir.Path("class/nd"))
 	if err == nil {
 		if len(devices) > 0 {
 			features["present"] = true
@@ -102,7 +118,7 @@ func detectNvdimm() (map[string]bool, error) {
 	}
 
 	// Check presence of DAX-configured regions
-	devices, err = ioutil.ReadDir(source.SysfsDir.Path("bus/nd/devices"))
+	devices, err = source.ReadDir(source.SysfsDir.Path("bus/nd/devices"))
 	if err == nil {
 		for _, d := range devices {
 			if strings.HasPrefix(d.Name(), "dax") {
@@ -110,7 +126,7 @@ func detectNvdimm() (map[string]bool, error) {
 			}
 		}
 	} else {
-		log.Printf("WARNING: failed to detect NVDIMM configuration: %s", err)
+		log.Warning("failed to detect NVDIMM configuration", "reason", err)
 	}
 
 	return features, nil
@@ -19,14 +19,15 @@ package network
 import (
 	"bytes"
 	"fmt"
-	"io/ioutil"
-	"log"
 	"strconv"
 	"strings"
 
+	"sigs.k8s.io/node-feature-discovery/pkg/logger"
 	"sigs.k8s.io/node-feature-discovery/source"
 )
 
+var log = logger.WithValues("source", "network")
+
 // Linux net iface flags (we only specify the first few)
 const (
 	flagUp = 1 << iota
@@ -37,26 +38,40 @@ const (
 
 const sysfsBaseDir = "class/net"
 
+// Configuration file options
+type Config struct {
+	source.LabelFilterConfig
+}
+
 // Source implements FeatureSource.
-type Source struct{}
+type Source struct {
+	config *Config
+}
 
 // Name returns an identifier string for this feature source.
 func (s Source) Name() string { return "network" }
 
 // NewConfig method of the FeatureSource interface
-func (s *Source) NewConfig() source.Config { return nil }
+func (s *Source) NewConfig() source.Config { return &Config{} }
 
 // GetConfig method of the FeatureSource interface
-func (s *Source) GetConfig() source.Config { return nil }
+func (s *Source) GetConfig() source.Config { return s.config }
 
 // SetConfig method of the FeatureSource interface
-func (s *Source) SetConfig(source.Config) {}
+func (s *Source) SetConfig(conf source.Config) {
+	switch v := conf.(type) {
+	case *Config:
+		s.config = v
+	default:
+		log.Error(fmt.Errorf("invalid config type: %T", conf), "failed to configure source")
+	}
+}
 
 // Discover returns feature names sriov-configured and sriov if SR-IOV capable NICs are present and/or SR-IOV virtual functions are configured on the node
 func (s Source) Discover() (source.Features, error) {
 	features := source.Features{}
 
-	netInterfaces, err := ioutil.ReadDir(source.SysfsDir.Path(sysfsBaseDir))
+	netInterfaces, err := source.ReadDir(source.SysfsDir.Path(sysfsBaseDir))
 	if err != nil {
 		return nil, fmt.Errorf("failed to list network interfaces: %s", err.Error())
 	}
@@ -66,43 +81,42 @@ func (s Source) Discover() (source.Features, error) {
 		name := netInterface.Name()
 		flags, err := readIfFlags(name)
 		if err != nil {
-			log.Printf("%v", err)
+			log.Error(err, "failed to read network interface flags", "interface", name)
 			continue
 		}
 
 		if flags&flagUp != 0 && flags&flagLoopback == 0 {
-			totalBytes, err := ioutil.ReadFile(source.SysfsDir.Path(sysfsBaseDir, name, "device/sriov_totalvfs"))
+			totalBytes, err := source.ReadFile(source.SysfsDir.Path(sysfsBaseDir, name, "device/sriov_totalvfs"))
 			if err != nil {
-				log.Printf("SR-IOV not supported for network interface: %s: %v", name, err)
+				log.V(1).Info("SR-IOV not supported for network interface", "interface", name, "reason", err)
 				continue
 			}
 			total := bytes.TrimSpace(totalBytes)
 			t, err := strconv.Atoi(string(total))
 			if err != nil {
-				log.Printf("Error in obtaining maximum supported number of virtual functions for network interface: %s: %v", name, err)
+				log.Error(err, "failed to obtain maximum supported number of virtual functions", "interface", name)
 				continue
 			}
 			if t > 0 {
-				log.Printf("SR-IOV capability is detected on the network interface: %s", name)
-				log.Printf("%d maximum supported number of virtual functions on network interface: %s", t, name)
+				log.V(1).Info("SR-IOV capability detected", "interface", name, "totalVFs", t)
 				features["sriov.capable"] = true
-				numBytes, err := ioutil.ReadFile(source.SysfsDir.Path(sysfsBaseDir, name, "device/sriov_numvfs"))
+				numBytes, err := source.ReadFile(source.SysfsDir.Path(sysfsBaseDir, name, "device/sriov_numvfs"))
 				if err != nil {
-					log.Printf("SR-IOV not configured for network interface: %s: %s", name, err)
+					log.V(1).Info("SR-IOV not configured for network interface", "interface", name, "reason", err)
 					continue
 				}
 				num := bytes.TrimSpace(numBytes)
 				n, err := strconv.Atoi(string(num))
 				if err != nil {
-					log.Printf("Error in obtaining the configured number of virtual functions for network interface: %s: %v", name, err)
+					log.Error(err, "failed to obtain the configured number of virtual functions", "interface", name)
 					continue
 				}
 				if n > 0 {
-					log.Printf("%d virtual functions configured on network interface: %s", n, name)
+					log.V(1).Info("virtual functions configured", "interface", name, "numVFs", n)
 					features["sriov.configured"] = true
 					break
 				} else if n == 0 {
-					log.Printf("SR-IOV not configured on network interface: %s", name)
+					log.V(1).Info("SR-IOV not configured on network interface", "interface", name)
 				}
 			}
 		}
@@ -111,7 +125,7 @@ func (s Source) Discover() (source.Features, error) {
 }
 
 func readIfFlags(name string) (uint64, error) {
-	raw, err := ioutil.ReadFile(source.SysfsDir.Path(sysfsBaseDir, name, "flags"))
+	raw, err := source.ReadFile(source.SysfsDir.Path(sysfsBaseDir, name, "flags"))
 	if err != nil {
 		return 0, fmt.Errorf("failed to read flags for interface %q: %v", name, err)
 	}
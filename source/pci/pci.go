@@ -18,16 +18,24 @@ package pci
 
 import (
 	"fmt"
-	"log"
+	"regexp"
 	"strings"
 
+	"sigs.k8s.io/node-feature-discovery/pkg/logger"
 	"sigs.k8s.io/node-feature-discovery/source"
 	pciutils "sigs.k8s.io/node-feature-discovery/source/internal"
 )
 
+var log = logger.WithValues("source", "pci")
+
+// Device classes are matched as hex prefixes of the class code
+var deviceClassRe = regexp.MustCompile(`^[0-9a-fA-F]{1,4}$`)
+
 type Config struct {
 	DeviceClassWhitelist []string `json:"deviceClassWhitelist,omitempty"`
 	DeviceLabelFields    []string `json:"deviceLabelFields,omitempty"`
+
+	source.LabelFilterConfig
 }
 
 // newDefaultConfig returns a new config with pre-populated defaults
@@ -38,6 +46,31 @@ func newDefaultConfig() *Config {
 	}
 }
 
+// Validate method of the ConfigValidator interface
+func (c *Config) Validate() []error {
+	errs := []error{}
+	for _, class := range c.DeviceClassWhitelist {
+		if !deviceClassRe.MatchString(class) {
+			errs = append(errs, fmt.Errorf("invalid PCI device class %q in deviceClassWhitelist, must be 1-4 hex digits", class))
+		}
+	}
+	for _, field := range c.DeviceLabelFields {
+		if !isValidDeviceLabelField(field) {
+			errs = append(errs, fmt.Errorf("invalid field %q in deviceLabelFields, must be one of %v", field, pciutils.DefaultPciDevAttrs))
+		}
+	}
+	return errs
+}
+
+func isValidDeviceLabelField(field string) bool {
+	for _, attr := range pciutils.DefaultPciDevAttrs {
+		if field == attr {
+			return true
+		}
+	}
+	return false
+}
+
 // Implement FeatureSource interface
 type Source struct {
 	config *Config
@@ -58,7 +91,7 @@ func (s *Source) SetConfig(conf source.Config) {
 	case *Config:
 		s.config = v
 	default:
-		log.Printf("PANIC: invalid config type: %T", conf)
+		log.Error(fmt.Errorf("invalid config type: %T", conf), "failed to configure source")
 	}
 }
 
@@ -84,10 +117,10 @@ func (s Source) Discover() (source.Features, error) {
 		for key := range configLabelFields {
 			keys = append(keys, key)
 		}
-		log.Printf("WARNING: invalid fields '%v' in deviceLabelFields, ignoring...", keys)
+		log.Warning("invalid fields in deviceLabelFields, ignoring", "fields", keys)
 	}
 	if len(deviceLabelFields) == 0 {
-		log.Printf("WARNING: no valid fields in deviceLabelFields defined, using the defaults")
+		log.Warning("no valid fields in deviceLabelFields defined, using the defaults")
 		deviceLabelFields = []string{"class", "vendor"}
 	}
 
@@ -0,0 +1,168 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sigs.k8s.io/node-feature-discovery/pkg/labelfilter"
+	"sigs.k8s.io/node-feature-discovery/pkg/logger"
+	pb "sigs.k8s.io/node-feature-discovery/pkg/plugin"
+	"sigs.k8s.io/node-feature-discovery/source"
+)
+
+var log = logger.WithValues("source", "plugin")
+
+// Timeout for calls to the plugin
+const callTimeout = 30 * time.Second
+
+// Config is the raw configuration of a plugin, passed to the plugin as-is
+type Config struct {
+	json.RawMessage
+}
+
+// GetLabelFilter method of the LabelFilterer interface. The labelFilter field
+// of a plugin config is applied by nfd-worker, the config is still passed to
+// the plugin as a whole.
+func (c *Config) GetLabelFilter() *labelfilter.Config {
+	raw := struct {
+		LabelFilter *labelfilter.Config `json:"labelFilter"`
+	}{}
+	if c == nil || json.Unmarshal(c.RawMessage, &raw) != nil {
+		return nil
+	}
+	return raw.LabelFilter
+}
+
+// Implement FeatureSource interface
+type Source struct {
+	name     string
+	endpoint string
+	conn     *grpc.ClientConn
+	client   pb.FeatureSourceClient
+	config   *Config
+
+	// unavailable is set when the plugin could not be reached on discovery,
+	// e.g. because it has exited
+	unavailableLock sync.Mutex
+	unavailable     bool
+}
+
+// Connect dials the FeatureSource service of a plugin listening on the given
+// Unix socket and returns a feature source backed by it.
+func Connect(endpoint string) (*Source, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout)
+	defer cancel()
+
+	dialer := func(ctx context.Context, addr string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, "unix", addr)
+	}
+	conn, err := grpc.DialContext(ctx, endpoint, grpc.WithInsecure(), grpc.WithBlock(),
+		grpc.FailOnNonTempDialError(true), grpc.WithContextDialer(dialer))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to plugin at %q: %v", endpoint, err)
+	}
+
+	s := &Source{endpoint: endpoint, conn: conn, client: pb.NewFeatureSourceClient(conn)}
+
+	reply, err := s.client.Name(ctx, &pb.NameRequest{})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to get name of plugin at %q: %v", endpoint, err)
+	}
+	if reply.Name == "" {
+		conn.Close()
+		return nil, fmt.Errorf("plugin at %q has an empty name", endpoint)
+	}
+	s.name = reply.Name
+
+	return s, nil
+}
+
+// Close closes the connection to the plugin
+func (s *Source) Close() error {
+	return s.conn.Close()
+}
+
+// Unavailable returns true if the plugin could not be reached on the last
+// discovery
+func (s *Source) Unavailable() bool {
+	s.unavailableLock.Lock()
+	defer s.unavailableLock.Unlock()
+	return s.unavailable
+}
+
+// Endpoint returns the Unix socket of the plugin
+func (s *Source) Endpoint() string { return s.endpoint }
+
+// Name method of the FeatureSource interface
+func (s *Source) Name() string { return s.name }
+
+// NewConfig method of the FeatureSource interface
+func (s *Source) NewConfig() source.Config { return &Config{} }
+
+// GetConfig method of the FeatureSource interface
+func (s *Source) GetConfig() source.Config { return s.config }
+
+// SetConfig method of the FeatureSource interface
+func (s *Source) SetConfig(conf source.Config) {
+	switch v := conf.(type) {
+	case *Config:
+		s.config = v
+	default:
+		log.Error(fmt.Errorf("invalid config type: %T", conf), "failed to configure source")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout)
+	defer cancel()
+
+	_, err := s.client.SetConfig(ctx, &pb.SetConfigRequest{Config: s.config.RawMessage})
+	if err != nil {
+		log.Error(err, "failed to configure plugin", "source", s.name)
+	}
+}
+
+// Discover method of the FeatureSource interface
+func (s *Source) Discover() (source.Features, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), callTimeout)
+	defer cancel()
+
+	reply, err := s.client.Discover(ctx, &pb.DiscoverRequest{})
+	if err != nil {
+		if status.Code(err) == codes.Unavailable {
+			s.unavailableLock.Lock()
+			s.unavailable = true
+			s.unavailableLock.Unlock()
+		}
+		return nil, fmt.Errorf("plugin discovery failed: %v", err)
+	}
+
+	features := source.Features{}
+	for k, v := range reply.Features {
+		features[k] = v
+	}
+	return features, nil
+}
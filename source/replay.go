@@ -0,0 +1,36 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"sigs.k8s.io/node-feature-discovery/pkg/cpuid"
+	"sigs.k8s.io/node-feature-discovery/source/internal/cpuidutils"
+)
+
+// GetCpuidFlags returns the CPU feature flags seen by the feature sources
+func GetCpuidFlags() []string {
+	return cpuidutils.GetCpuidFlags()
+}
+
+// SetCpuidReplay makes the feature sources use CPU information captured on
+// another node, i.e. the CPU feature flags and the results of CPUID queries,
+// instead of inspecting the CPU they are running on. Nil values restore the
+// default behavior.
+func SetCpuidReplay(flags []string, records []cpuid.Record) {
+	cpuidutils.SetReplayFlags(flags)
+	cpuid.SetReplay(records)
+}
@@ -16,6 +16,10 @@ limitations under the License.
 
 package source
 
+import (
+	"sigs.k8s.io/node-feature-discovery/pkg/labelfilter"
+)
+
 // Value of a feature
 type FeatureValue interface {
 }
@@ -52,3 +56,45 @@ type FeatureSource interface {
 
 type Config interface {
 }
+
+// ConfigValidator is implemented by source configs that are able to check
+// their semantic validity
+type ConfigValidator interface {
+	// Validate returns the errors found in the configuration
+	Validate() []error
+}
+
+// DroppedFeature is a candidate feature that a source does not return from
+// Discover
+type DroppedFeature struct {
+	// Name of the feature
+	Name string
+	// Reason why the feature was dropped
+	Reason string
+}
+
+// FeatureExplainer is implemented by feature sources that drop candidate
+// features internally, e.g. based on their configuration.
+type FeatureExplainer interface {
+	// DroppedFeatures returns the candidate features that Discover does not
+	// return, together with the reason for dropping them.
+	DroppedFeatures() ([]DroppedFeature, error)
+}
+
+// LabelFilterConfig adds the labelFilter option to the config of a source
+// when embedded in it. Features not passing the filter are dropped by
+// nfd-worker.
+type LabelFilterConfig struct {
+	LabelFilter *labelfilter.Config `json:"labelFilter,omitempty"`
+}
+
+// GetLabelFilter method of the LabelFilterer interface
+func (c *LabelFilterConfig) GetLabelFilter() *labelfilter.Config { return c.LabelFilter }
+
+// LabelFilterer is implemented by source configs supporting the labelFilter
+// option
+type LabelFilterer interface {
+	// GetLabelFilter returns the configuration of the label filter, nil if
+	// not set
+	GetLabelFilter() *labelfilter.Config
+}
@@ -18,36 +18,52 @@ package storage
 
 import (
 	"fmt"
-	"io/ioutil"
 
+	"sigs.k8s.io/node-feature-discovery/pkg/logger"
 	"sigs.k8s.io/node-feature-discovery/source"
 )
 
+var log = logger.WithValues("source", "storage")
+
+// Configuration file options
+type Config struct {
+	source.LabelFilterConfig
+}
+
 // Source implements FeatureSource.
-type Source struct{}
+type Source struct {
+	config *Config
+}
 
 // Name returns an identifier string for this feature source.
 func (s Source) Name() string { return "storage" }
 
 // NewConfig method of the FeatureSource interface
-func (s *Source) NewConfig() source.Config { return nil }
+func (s *Source) NewConfig() source.Config { return &Config{} }
 
 // GetConfig method of the FeatureSource interface
-func (s *Source) GetConfig() source.Config { return nil }
+func (s *Source) GetConfig() source.Config { return s.config }
 
 // SetConfig method of the FeatureSource interface
-func (s *Source) SetConfig(source.Config) {}
+func (s *Source) SetConfig(conf source.Config) {
+	switch v := conf.(type) {
+	case *Config:
+		s.config = v
+	default:
+		log.Error(fmt.Errorf("invalid config type: %T", conf), "failed to configure source")
+	}
+}
 
 // Discover returns feature names for storage: nonrotationaldisk if any SSD drive present.
 func (s Source) Discover() (source.Features, error) {
 	features := source.Features{}
 
 	// Check if there is any non-rotational block devices attached to the node
-	blockdevices, err := ioutil.ReadDir(source.SysfsDir.Path("block"))
+	blockdevices, err := source.ReadDir(source.SysfsDir.Path("block"))
 	if err == nil {
 		for _, bdev := range blockdevices {
 			fname := source.SysfsDir.Path("block", bdev.Name(), "queue/rotational")
-			bytes, err := ioutil.ReadFile(fname)
+			bytes, err := source.ReadFile(fname)
 			if err != nil {
 				return nil, fmt.Errorf("can't read rotational status: %s", err.Error())
 			}
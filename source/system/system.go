@@ -18,39 +18,55 @@ package system
 
 import (
 	"bufio"
-	"log"
-	"os"
+	"fmt"
 	"regexp"
 	"strings"
 
+	"sigs.k8s.io/node-feature-discovery/pkg/logger"
 	"sigs.k8s.io/node-feature-discovery/source"
 )
 
+var log = logger.WithValues("source", "system")
+
 var osReleaseFields = [...]string{
 	"ID",
 	"VERSION_ID",
 }
 
+// Configuration file options
+type Config struct {
+	source.LabelFilterConfig
+}
+
 // Implement FeatureSource interface
-type Source struct{}
+type Source struct {
+	config *Config
+}
 
 func (s Source) Name() string { return "system" }
 
 // NewConfig method of the FeatureSource interface
-func (s *Source) NewConfig() source.Config { return nil }
+func (s *Source) NewConfig() source.Config { return &Config{} }
 
 // GetConfig method of the FeatureSource interface
-func (s *Source) GetConfig() source.Config { return nil }
+func (s *Source) GetConfig() source.Config { return s.config }
 
 // SetConfig method of the FeatureSource interface
-func (s *Source) SetConfig(source.Config) {}
+func (s *Source) SetConfig(conf source.Config) {
+	switch v := conf.(type) {
+	case *Config:
+		s.config = v
+	default:
+		log.Error(fmt.Errorf("invalid config type: %T", conf), "failed to configure source")
+	}
+}
 
 func (s Source) Discover() (source.Features, error) {
 	features := source.Features{}
 
 	release, err := parseOSRelease()
 	if err != nil {
-		log.Printf("ERROR: failed to get os-release: %s", err)
+		log.Error(err, "failed to get os-release")
 	} else {
 		for _, key := range osReleaseFields {
 			if value, exists := release[key]; exists {
@@ -75,7 +91,7 @@ func (s Source) Discover() (source.Features, error) {
 func parseOSRelease() (map[string]string, error) {
 	release := map[string]string{}
 
-	f, err := os.Open(source.EtcDir.Path("os-release"))
+	f, err := source.Open(source.EtcDir.Path("os-release"))
 	if err != nil {
 		return nil, err
 	}
@@ -18,16 +18,24 @@ package usb
 
 import (
 	"fmt"
-	"log"
+	"regexp"
 	"strings"
 
+	"sigs.k8s.io/node-feature-discovery/pkg/logger"
 	"sigs.k8s.io/node-feature-discovery/source"
 	usbutils "sigs.k8s.io/node-feature-discovery/source/internal"
 )
 
+var log = logger.WithValues("source", "usb")
+
+// Device classes are matched as hex prefixes of the class code
+var deviceClassRe = regexp.MustCompile(`^[0-9a-fA-F]{1,2}$`)
+
 type Config struct {
 	DeviceClassWhitelist []string `json:"deviceClassWhitelist,omitempty"`
 	DeviceLabelFields    []string `json:"deviceLabelFields,omitempty"`
+
+	source.LabelFilterConfig
 }
 
 // newDefaultConfig returns a new config with pre-populated defaults
@@ -41,6 +49,31 @@ func newDefaultConfig() *Config {
 	}
 }
 
+// Validate method of the ConfigValidator interface
+func (c *Config) Validate() []error {
+	errs := []error{}
+	for _, class := range c.DeviceClassWhitelist {
+		if !deviceClassRe.MatchString(class) {
+			errs = append(errs, fmt.Errorf("invalid USB device class %q in deviceClassWhitelist, must be 1-2 hex digits", class))
+		}
+	}
+	for _, field := range c.DeviceLabelFields {
+		if !isValidDeviceLabelField(field) {
+			errs = append(errs, fmt.Errorf("invalid field %q in deviceLabelFields, must be one of %v", field, usbutils.DefaultUsbDevAttrs))
+		}
+	}
+	return errs
+}
+
+func isValidDeviceLabelField(field string) bool {
+	for _, attr := range usbutils.DefaultUsbDevAttrs {
+		if field == attr {
+			return true
+		}
+	}
+	return false
+}
+
 // Implement FeatureSource interface
 type Source struct {
 	config *Config
@@ -61,7 +94,7 @@ func (s *Source) SetConfig(conf source.Config) {
 	case *Config:
 		s.config = v
 	default:
-		log.Printf("PANIC: invalid config type: %T", conf)
+		log.Error(fmt.Errorf("invalid config type: %T", conf), "failed to configure source")
 	}
 }
 
@@ -87,10 +120,10 @@ func (s Source) Discover() (source.Features, error) {
 		for key := range configLabelFields {
 			keys = append(keys, key)
 		}
-		log.Printf("WARNING: invalid fields '%v' in deviceLabelFields, ignoring...", keys)
+		log.Warning("invalid fields in deviceLabelFields, ignoring", "fields", keys)
 	}
 	if len(deviceLabelFields) == 0 {
-		log.Printf("WARNING: no valid fields in deviceLabelFields defined, using the defaults")
+		log.Warning("no valid fields in deviceLabelFields defined, using the defaults")
 		deviceLabelFields = []string{"vendor", "device"}
 	}
 
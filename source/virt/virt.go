@@ -0,0 +1,170 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virt
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/logger"
+	"sigs.k8s.io/node-feature-discovery/source"
+)
+
+var log = logger.WithValues("source", "virt")
+
+// Virtualization types, in addition to the hypervisor names
+const (
+	typeNone  = "none"
+	typeOther = "other"
+)
+
+// dmiHypervisors maps DMI system vendors, and optionally product names, to
+// hypervisors. The first entry whose vendor and product are substrings of the
+// DMI values is used.
+var dmiHypervisors = []struct {
+	vendor     string
+	product    string
+	hypervisor string
+}{
+	{"", "KVM", "kvm"},
+	{"QEMU", "", "qemu"},
+	{"Google", "Google Compute Engine", "kvm"},
+	{"Microsoft Corporation", "Virtual Machine", "hyperv"},
+	{"VMware", "", "vmware"},
+	{"Xen", "", "xen"},
+	{"innotek GmbH", "", "virtualbox"},
+	{"Parallels", "", "parallels"},
+	{"BHYVE", "", "bhyve"},
+}
+
+// Configuration file options
+type Config struct {
+	source.LabelFilterConfig
+}
+
+// Source implements FeatureSource.
+type Source struct {
+	config *Config
+}
+
+// Name returns an identifier string for this feature source.
+func (s *Source) Name() string { return "virt" }
+
+// NewConfig method of the FeatureSource interface
+func (s *Source) NewConfig() source.Config { return &Config{} }
+
+// GetConfig method of the FeatureSource interface
+func (s *Source) GetConfig() source.Config { return s.config }
+
+// SetConfig method of the FeatureSource interface
+func (s *Source) SetConfig(conf source.Config) {
+	switch v := conf.(type) {
+	case *Config:
+		s.config = v
+	default:
+		log.Error(fmt.Errorf("invalid config type: %T", conf), "failed to configure source")
+	}
+}
+
+// Discover returns feature names for the virtualization environment of the
+// node and the virtualization capabilities it provides.
+func (s *Source) Discover() (source.Features, error) {
+	features := source.Features{}
+
+	virtType, err := detectType()
+	if err != nil {
+		log.Error(err, "failed to detect virtualization type")
+	} else {
+		features["type"] = virtType
+	}
+
+	// KVM is usable if the device node exists
+	if _, err := source.Stat(source.DevDir.Path("kvm")); err == nil {
+		features["kvm.enabled"] = true
+	}
+	if moduleParamEnabled("kvm_intel", "nested") || moduleParamEnabled("kvm_amd", "nested") {
+		features["kvm.nested"] = true
+	}
+
+	return features, nil
+}
+
+// detectType detects the hypervisor the node is running on, from CPUID and,
+// if that is not conclusive, from DMI
+func detectType() (string, error) {
+	virtType := cpuidHypervisor()
+	if virtType != "" && virtType != typeOther {
+		return virtType, nil
+	}
+
+	hypervisor, err := dmiHypervisor()
+	if err != nil {
+		if virtType != "" {
+			return virtType, nil
+		}
+		return "", err
+	}
+	switch {
+	case hypervisor != "":
+		return hypervisor, nil
+	case virtType != "":
+		return virtType, nil
+	}
+	return typeNone, nil
+}
+
+// dmiHypervisor returns the hypervisor identified by the DMI system vendor
+// and product name, or an empty string if the DMI information does not match
+// any known hypervisor. Systems without DMI, e.g. many non-x86 hosts, are not
+// considered to be running on a hypervisor.
+func dmiHypervisor() (string, error) {
+	vendor, err := readDMI("sys_vendor")
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	product, err := readDMI("product_name")
+	if err != nil {
+		return "", err
+	}
+	for _, d := range dmiHypervisors {
+		if strings.Contains(vendor, d.vendor) && strings.Contains(product, d.product) {
+			return d.hypervisor, nil
+		}
+	}
+	return "", nil
+}
+
+func readDMI(name string) (string, error) {
+	data, err := source.ReadFile(source.SysfsDir.Path("class/dmi/id", name))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// moduleParamEnabled returns true if a boolean kernel module parameter is set
+func moduleParamEnabled(module, param string) bool {
+	data, err := source.ReadFile(source.SysfsDir.Path("module", module, "parameters", param))
+	if err != nil {
+		return false
+	}
+	v := strings.TrimSpace(string(data))
+	return v == "Y" || v == "1"
+}
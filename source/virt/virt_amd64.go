@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package virt
+
+import (
+	"encoding/binary"
+
+	"sigs.k8s.io/node-feature-discovery/pkg/cpuid"
+)
+
+const (
+	// CPUID EAX input values
+	LEAF_PROCESSOR_VERSION = 0x01
+	LEAF_HYPERVISOR_VENDOR = 0x40000000
+
+	// CPUID bitmasks
+	PROCESSOR_VERSION_ECX_HYPERVISOR = 1 << 31
+)
+
+// Hypervisor vendor signatures reported in the CPUID hypervisor leaf
+var hypervisorSignatures = map[string]string{
+	"KVMKVMKVM\x00\x00\x00": "kvm",
+	"Microsoft Hv":          "hyperv",
+	"VMwareVMware":          "vmware",
+	"XenVMMXenVMM":          "xen",
+	"TCGTCGTCGTCG":          "qemu",
+	"VBoxVBoxVBox":          "virtualbox",
+	" lrpepyh  vr":          "parallels",
+	"bhyve bhyve ":          "bhyve",
+	"ACRNACRNACRN":          "acrn",
+}
+
+// cpuidHypervisor returns the hypervisor reported by CPUID, "none" if CPUID
+// reports that no hypervisor is present, or "other" for an unknown hypervisor
+func cpuidHypervisor() string {
+	if cpuid.Cpuid(LEAF_PROCESSOR_VERSION, 0).ECX&PROCESSOR_VERSION_ECX_HYPERVISOR == 0 {
+		return typeNone
+	}
+
+	// Vendor signature is stored in EBX, ECX and EDX, in that order
+	vendor := cpuid.Cpuid(LEAF_HYPERVISOR_VENDOR, 0)
+	buf := make([]byte, 12)
+	binary.LittleEndian.PutUint32(buf[0:], vendor.EBX)
+	binary.LittleEndian.PutUint32(buf[4:], vendor.ECX)
+	binary.LittleEndian.PutUint32(buf[8:], vendor.EDX)
+	if hypervisor, ok := hypervisorSignatures[string(buf)]; ok {
+		return hypervisor
+	}
+	return typeOther
+}